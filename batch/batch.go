@@ -0,0 +1,255 @@
+// Package batch scores large streams of rows with a booster, for offline jobs. Rows are
+// grouped into micro-batches by size or time and predicted on a bounded pool of
+// workers, each with its own copy of the booster since libxgboost predictions of one
+// booster cannot run concurrently.
+package batch
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+// Row is a dense row to predict, NaN features are missing. Index identifies the row in
+// its Result, as results are not in the order of rows.
+type Row struct {
+	Index    int64
+	Features []float32
+}
+
+// Result is the prediction of a row, all the values Booster.Predict gives for it
+type Result struct {
+	Index       int64
+	Predictions []float32
+}
+
+// Options configure a Predictor
+type Options struct {
+	// BatchSize is the most rows predicted at once, 512 by default
+	BatchSize int
+	// MaxWait is the longest a batch waits to fill once it has a row, 50ms by default
+	MaxWait time.Duration
+	// Workers is the number of batches predicted at once, the number of CPUs by default
+	Workers int
+	// Threads is the nthread of the booster of every worker, by default the number of
+	// CPUs shared out among the workers so that they do not oversubscribe them
+	Threads int
+	// OptionMask and NTreeLimit are passed to Booster.Predict
+	OptionMask int
+	NTreeLimit uint
+}
+
+// Predictor predicts streams of rows with a booster
+type Predictor struct {
+	opts     Options
+	boosters chan *xgboost.Booster
+	metrics  metrics
+}
+
+// NewPredictor create a predictor copying the model of booster for every worker, so
+// booster may be freed or changed afterwards. The predictor must be closed.
+func NewPredictor(booster *xgboost.Booster, opts Options) (*Predictor, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 512
+	}
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = 50 * time.Millisecond
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = runtime.NumCPU() / opts.Workers
+		if opts.Threads < 1 {
+			opts.Threads = 1
+		}
+	}
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return nil, err
+	}
+	p := &Predictor{opts: opts, boosters: make(chan *xgboost.Booster, opts.Workers)}
+	for i := 0; i < opts.Workers; i++ {
+		worker, err := xgboost.BoosterCreate(nil)
+		if err == nil {
+			if err = worker.LoadModelFromBuffer(raw); err == nil {
+				err = worker.SetParam("nthread", strconv.Itoa(opts.Threads))
+			}
+			if err != nil {
+				worker.Free()
+			}
+		}
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.boosters <- worker
+	}
+	return p, nil
+}
+
+// Close free the copies of the booster, once no Run is in progress
+func (p *Predictor) Close() error {
+	for {
+		select {
+		case booster := <-p.boosters:
+			booster.Free()
+		default:
+			return nil
+		}
+	}
+}
+
+// batch is a micro-batch of rows, with when its first row arrived
+type batch struct {
+	rows  []Row
+	start time.Time
+}
+
+// Run predict the rows received until rows is closed and send their results, then
+// return once every result is sent. It stops early with the error of a prediction or of
+// ctx, when it is canceled or past its deadline; results of rows already predicted may
+// then be dropped. Run does not close results. Several runs may share the predictor,
+// sharing its workers.
+func (p *Predictor) Run(ctx context.Context, rows <-chan Row, results chan<- Result) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p.metrics.start()
+
+	var (
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	batches := make(chan batch)
+	var wg sync.WaitGroup
+	for i := 0; i < p.opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := range batches {
+				if err := p.predict(ctx, b, results); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	p.collect(ctx, rows, batches)
+	close(batches)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// collect group rows into batches until rows is closed or ctx is done
+func (p *Predictor) collect(ctx context.Context, rows <-chan Row, batches chan<- batch) {
+	var (
+		current batch
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+	flush := func() bool {
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if len(current.rows) == 0 {
+			return true
+		}
+		select {
+		case batches <- current:
+			current = batch{}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case row, ok := <-rows:
+			if !ok {
+				flush()
+				return
+			}
+			if len(current.rows) == 0 {
+				current = batch{rows: make([]Row, 0, p.opts.BatchSize), start: time.Now()}
+				timer = time.NewTimer(p.opts.MaxWait)
+				timeout = timer.C
+			}
+			current.rows = append(current.rows, row)
+			if len(current.rows) >= p.opts.BatchSize && !flush() {
+				return
+			}
+		case <-timeout:
+			timer, timeout = nil, nil
+			if !flush() {
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// predict predict a batch on a free worker booster and send the results
+func (p *Predictor) predict(ctx context.Context, b batch, results chan<- Result) error {
+	cols := 0
+	for _, row := range b.rows {
+		if len(row.Features) > cols {
+			cols = len(row.Features)
+		}
+	}
+	if cols == 0 {
+		return errors.New("rows have no features")
+	}
+	// rows shorter than the longest are padded with missing values
+	data := make([]float32, len(b.rows)*cols)
+	for i, row := range b.rows {
+		n := copy(data[i*cols:], row.Features)
+		for j := n; j < cols; j++ {
+			data[i*cols+j] = float32(math.NaN())
+		}
+	}
+
+	var booster *xgboost.Booster
+	select {
+	case booster = <-p.boosters:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	predictStart := time.Now()
+	preds, err := booster.PredictDense(data, len(b.rows), cols, p.opts.OptionMask, p.opts.NTreeLimit)
+	predictTime := time.Since(predictStart)
+	p.boosters <- booster
+	if err != nil {
+		p.metrics.fail()
+		return err
+	}
+
+	perRow := len(preds) / len(b.rows)
+	for i, row := range b.rows {
+		select {
+		case results <- Result{Index: row.Index, Predictions: preds[i*perRow : (i+1)*perRow : (i+1)*perRow]}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	p.metrics.batch(len(b.rows), predictTime, time.Since(b.start))
+	return nil
+}
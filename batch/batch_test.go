@@ -0,0 +1,182 @@
+package batch
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+func testBooster(t *testing.T) (*xgboost.Booster, model.Matrix) {
+	rows := make(model.Matrix, 1000)
+	labels := make([]float32, len(rows))
+	for i := range rows {
+		rows[i] = []float32{float32(i % 10), float32(i%7) * 0.5, float32((i * 3) % 11)}
+		if i%5 == 0 {
+			rows[i][i%3] = float32(math.NaN())
+		}
+		labels[i] = float32(i % 3)
+	}
+	dm, err := xgboost.DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dm.Free()
+	if err := dm.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	params := xgboost.Params{"objective": "multi:softprob", "num_class": "3", "max_depth": "3", "silent": "1"}
+	booster, err := xgboost.Train(params, dm, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return booster, rows
+}
+
+func TestRun(t *testing.T) {
+	booster, rows := testBooster(t)
+	defer booster.Free()
+	dm, err := xgboost.DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dm.Free()
+	want, err := booster.Predict(dm, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewPredictor(booster, Options{BatchSize: 64, Workers: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if threads := p.opts.Threads; threads < 1 || (threads > 1 && threads*4 > runtime.NumCPU()) {
+		t.Errorf("%d threads for each of 4 workers on %d CPUs", threads, runtime.NumCPU())
+	}
+	input := make(chan Row)
+	results := make(chan Result, len(rows))
+	go func() {
+		for i, row := range rows {
+			input <- Row{Index: int64(i), Features: row}
+		}
+		close(input)
+	}()
+	if err := p.Run(context.Background(), input, results); err != nil {
+		t.Fatal(err)
+	}
+	close(results)
+
+	seen := map[int64]bool{}
+	for result := range results {
+		if seen[result.Index] {
+			t.Fatalf("row %d predicted twice", result.Index)
+		}
+		seen[result.Index] = true
+		for j, v := range result.Predictions {
+			if v != want[int(result.Index)*3+j] {
+				t.Fatalf("row %d: got %v, expected %v", result.Index, result.Predictions, want[result.Index*3:result.Index*3+3])
+			}
+		}
+	}
+	if len(seen) != len(rows) {
+		t.Errorf("got %d results for %d rows", len(seen), len(rows))
+	}
+
+	m := p.Metrics()
+	if m.Rows != int64(len(rows)) || m.Batches < int64(len(rows)/64) || m.Errors != 0 {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if m.Throughput() <= 0 || m.PredictLatency <= 0 || m.MaxBatchLatency < m.BatchLatency {
+		t.Errorf("unexpected latencies %+v", m)
+	}
+}
+
+func TestRunMaxWait(t *testing.T) {
+	booster, rows := testBooster(t)
+	defer booster.Free()
+	p, err := NewPredictor(booster, Options{BatchSize: 1000, MaxWait: 5 * time.Millisecond, Workers: 1, Threads: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// a partial batch is predicted without closing the input
+	input := make(chan Row)
+	results := make(chan Result)
+	done := make(chan error)
+	go func() { done <- p.Run(context.Background(), input, results) }()
+	for i := 0; i < 3; i++ {
+		input <- Row{Index: int64(i), Features: rows[i]}
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-results:
+		case <-time.After(5 * time.Second):
+			t.Fatal("partial batch not predicted")
+		}
+	}
+	close(input)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	booster, rows := testBooster(t)
+	defer booster.Free()
+	p, err := NewPredictor(booster, Options{BatchSize: 8, Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// nobody reads the results, so the run only ends through its context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	input := make(chan Row)
+	go func() {
+		for i, row := range rows {
+			select {
+			case input <- Row{Index: int64(i), Features: row}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	if err := p.Run(ctx, input, make(chan Result)); err != context.DeadlineExceeded {
+		t.Errorf("got %v, expected %v", err, context.DeadlineExceeded)
+	}
+
+	// the workers are back in the pool for the next run
+	input = make(chan Row, 1)
+	input <- Row{Features: rows[0]}
+	close(input)
+	results := make(chan Result, 1)
+	if err := p.Run(context.Background(), input, results); err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Error("no result after a canceled run")
+	}
+}
+
+func TestRunError(t *testing.T) {
+	booster, _ := testBooster(t)
+	defer booster.Free()
+	p, err := NewPredictor(booster, Options{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	input := make(chan Row, 1)
+	input <- Row{}
+	close(input)
+	if err := p.Run(context.Background(), input, make(chan Result, 1)); err == nil {
+		t.Error("expected an error for a row without features")
+	}
+}
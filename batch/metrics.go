@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics describe the work of a predictor since it was created
+type Metrics struct {
+	Rows    int64
+	Batches int64
+	// Errors counts the batches whose prediction failed
+	Errors int64
+	// Elapsed is the time since the first run started
+	Elapsed time.Duration
+	// PredictLatency is the mean and largest time predicting a batch took
+	PredictLatency    time.Duration
+	MaxPredictLatency time.Duration
+	// BatchLatency is the mean and largest time from the first row of a batch arriving
+	// to the results of the batch being sent
+	BatchLatency    time.Duration
+	MaxBatchLatency time.Duration
+}
+
+// Throughput get the rows predicted per second
+func (m Metrics) Throughput() float64 {
+	if m.Elapsed <= 0 {
+		return 0
+	}
+	return float64(m.Rows) / m.Elapsed.Seconds()
+}
+
+type metrics struct {
+	mu           sync.Mutex
+	started      time.Time
+	rows         int64
+	batches      int64
+	errors       int64
+	predictTotal time.Duration
+	predictMax   time.Duration
+	batchTotal   time.Duration
+	batchMax     time.Duration
+}
+
+func (m *metrics) start() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started.IsZero() {
+		m.started = time.Now()
+	}
+}
+
+func (m *metrics) fail() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors++
+}
+
+func (m *metrics) batch(rows int, predictLatency, batchLatency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows += int64(rows)
+	m.batches++
+	m.predictTotal += predictLatency
+	m.batchTotal += batchLatency
+	if predictLatency > m.predictMax {
+		m.predictMax = predictLatency
+	}
+	if batchLatency > m.batchMax {
+		m.batchMax = batchLatency
+	}
+}
+
+// Metrics get the metrics of the predictor so far
+func (p *Predictor) Metrics() Metrics {
+	m := &p.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	metrics := Metrics{
+		Rows:              m.rows,
+		Batches:           m.batches,
+		Errors:            m.errors,
+		MaxPredictLatency: m.predictMax,
+		MaxBatchLatency:   m.batchMax,
+	}
+	if !m.started.IsZero() {
+		metrics.Elapsed = time.Since(m.started)
+	}
+	if m.batches > 0 {
+		metrics.PredictLatency = m.predictTotal / time.Duration(m.batches)
+		metrics.BatchLatency = m.batchTotal / time.Duration(m.batches)
+	}
+	return metrics
+}
@@ -107,7 +107,7 @@ func (booster *Booster) EvalOneIter(iter int, dmats []*DMatrix, evnames []string
 			C.free(unsafe.Pointer(v))
 		}
 	}()
-	ret := C.XGBoosterEvalOneIter(booster.handle, C.int(iter), (*C.DMatrixHandle)(unsafe.Pointer(handles[0])), (**C.char)(unsafe.Pointer(&evnamesC[0])), dmatsLenC, (**C.char)(unsafe.Pointer(&resultC)))
+	ret := C.XGBoosterEvalOneIter(booster.handle, C.int(iter), (*C.DMatrixHandle)(unsafe.Pointer(&handles[0])), (**C.char)(unsafe.Pointer(&evnamesC[0])), dmatsLenC, (**C.char)(unsafe.Pointer(&resultC)))
 	if err := checkError(ret); err != nil {
 		return "", err
 	}
@@ -115,23 +115,7 @@ func (booster *Booster) EvalOneIter(iter int, dmats []*DMatrix, evnames []string
 	return result, nil
 }
 func (booster *Booster) Predict(dMatrix *DMatrix, optionMask int, ntreeLimit uint) (result []float32, err error) {
-	var (
-		outPtr *C.float
-		outLen C.bst_ulong
-	)
-	ret := C.XGBoosterPredict(booster.handle, dMatrix.handle, C.int(optionMask), C.unsigned(ntreeLimit), &outLen, &outPtr)
-	if err := checkError(ret); err != nil {
-		return nil, err
-	}
-	p := outPtr
-	ptrSize := unsafe.Sizeof(*p)
-	var i C.bst_ulong
-	for i < outLen {
-		result = append(result, float32(*p))
-		p = (*C.float)(unsafe.Pointer(uintptr(unsafe.Pointer(p)) + uintptr(ptrSize)))
-		i += 1
-	}
-	return result, nil
+	return booster.predictHandle(dMatrix.handle, optionMask, ntreeLimit)
 }
 
 // LoadModel load model from existing file
@@ -334,6 +318,14 @@ func (booster *Booster) SetAttr(key string, value string) error {
 	return checkError(ret)
 }
 
+// DeleteAttr remove the attribute key from the booster
+func (booster *Booster) DeleteAttr(key string) error {
+	keyC := C.CString(key)
+	defer C.free(unsafe.Pointer(keyC))
+	ret := C.XGBoosterSetAttr(booster.handle, keyC, nil)
+	return checkError(ret)
+}
+
 func (booster *Booster) GetAttrNames() (result []string, err error) {
 	var (
 		outPtr **C.char
@@ -359,9 +351,15 @@ func (booster *Booster) LoadModelFromBuffer(buffer []byte) error {
 	return checkError(ret)
 }
 
-func (booster *Booster) LoadRabitCheckpoint(version int) error {
-	ret := C.XGBoosterLoadRabitCheckpoint(booster.handle, (*C.int)((unsafe.Pointer)(&version)))
-	return checkError(ret)
+// LoadRabitCheckpoint load the latest rabit checkpoint into the booster and return its
+// version, the number of checkpoints taken so far; 0 means there was none to load.
+func (booster *Booster) LoadRabitCheckpoint() (version int, err error) {
+	var versionC C.int
+	ret := C.XGBoosterLoadRabitCheckpoint(booster.handle, &versionC)
+	if err := checkError(ret); err != nil {
+		return 0, err
+	}
+	return int(versionC), nil
 }
 
 func (booster *Booster) SaveRabitCheckpoint() error {
@@ -0,0 +1,273 @@
+package xgboost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrStopTraining is returned by a callback to stop training after the current step;
+// TrainContext then returns the booster without error
+var ErrStopTraining = errors.New("stop training")
+
+// TrainInfo is the state of training passed to callbacks
+type TrainInfo struct {
+	Booster *Booster
+	// Iteration is the current round, from 0, or numbered after the rounds already in
+	// the model when training continues
+	Iteration int
+	// Rounds is the number of rounds of the model once trained
+	Rounds int
+	// ModelRounds is the number of rounds in the model after the current round,
+	// Iteration+1 unless process_type=update rewrites the rounds in place
+	ModelRounds int
+	// Evals are the results of evaluating the eval sets after the round, set for
+	// AfterEvaluation only
+	Evals []EvalResult
+}
+
+// Callback observe or steer training. Every round TrainContext calls BeforeIteration,
+// boosts, calls AfterIteration, evaluates the eval sets if any and calls
+// AfterEvaluation. A callback returning ErrStopTraining stops training cleanly, any
+// other error aborts it.
+type Callback interface {
+	BeforeIteration(info *TrainInfo) error
+	AfterIteration(info *TrainInfo) error
+	AfterEvaluation(info *TrainInfo) error
+}
+
+// CallbackFuncs is a Callback of functions, nil ones doing nothing
+type CallbackFuncs struct {
+	Before    func(info *TrainInfo) error
+	After     func(info *TrainInfo) error
+	AfterEval func(info *TrainInfo) error
+}
+
+func (c CallbackFuncs) BeforeIteration(info *TrainInfo) error { return callFunc(c.Before, info) }
+func (c CallbackFuncs) AfterIteration(info *TrainInfo) error  { return callFunc(c.After, info) }
+func (c CallbackFuncs) AfterEvaluation(info *TrainInfo) error { return callFunc(c.AfterEval, info) }
+
+func callFunc(f func(*TrainInfo) error, info *TrainInfo) error {
+	if f == nil {
+		return nil
+	}
+	return f(info)
+}
+
+// EvalSet is a named data set evaluated after every round
+type EvalSet struct {
+	Name string
+	Data *DMatrix
+}
+
+// TrainOptions configure TrainContext
+type TrainOptions struct {
+	Evals     []EvalSet
+	Callbacks []Callback
+}
+
+// TrainContext create a booster with params and boost it up to rounds times on dtrain
+// like Train, evaluating opts.Evals and firing opts.Callbacks every round. Training
+// stops between rounds when ctx is done: the booster is then returned along with the
+// error of ctx, holding the rounds boosted so far, and must still be freed.
+func TrainContext(ctx context.Context, params Params, dtrain *DMatrix, rounds int, opts TrainOptions) (*Booster, error) {
+	booster, err := createTrainBooster(dtrain, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := booster.SetParams(params); err != nil {
+		booster.Free()
+		return nil, err
+	}
+	done, err := booster.trainContext(ctx, dtrain, 0, rounds, 0, opts)
+	return booster.recordRounds(ctx, done, err)
+}
+
+// ContinueTrainingContext is ContinueTraining with the evaluations, callbacks and
+// cancellation of TrainContext
+func ContinueTrainingContext(ctx context.Context, model []byte, dtrain *DMatrix, extraRounds int, params Params, opts TrainOptions) (*Booster, error) {
+	if len(model) == 0 {
+		return nil, errors.New("empty model")
+	}
+	booster, err := createTrainBooster(dtrain, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := booster.LoadModelFromBuffer(model); err != nil {
+		booster.Free()
+		return nil, err
+	}
+	if err := booster.SetParams(params); err != nil {
+		booster.Free()
+		return nil, err
+	}
+	start, err := booster.BoostedRounds(params)
+	if err != nil {
+		booster.Free()
+		return nil, err
+	}
+
+	if params["process_type"] != "update" {
+		done, err := booster.trainContext(ctx, dtrain, start, extraRounds, 0, opts)
+		return booster.recordRounds(ctx, done, err)
+	}
+	if extraRounds > start {
+		booster.Free()
+		return nil, fmt.Errorf("cannot update %d rounds, the model only has %d", extraRounds, start)
+	}
+	// updates rewrite the first rounds in place, the model keeps its round count
+	_, err = booster.trainContext(ctx, dtrain, 0, extraRounds, start, opts)
+	return booster.recordRounds(ctx, start, err)
+}
+
+// createTrainBooster create a booster caching dtrain and the eval sets
+func createTrainBooster(dtrain *DMatrix, opts TrainOptions) (*Booster, error) {
+	matrices := []*DMatrix{dtrain}
+	for _, eval := range opts.Evals {
+		matrices = append(matrices, eval.Data)
+	}
+	return BoosterCreate(matrices)
+}
+
+// recordRounds record the rounds of a booster whose training ended with err, keeping
+// it only when err is nil or the error of ctx
+func (booster *Booster) recordRounds(ctx context.Context, rounds int, err error) (*Booster, error) {
+	if err != nil && err != ctx.Err() {
+		booster.Free()
+		return nil, err
+	}
+	if err := booster.SetAttr(boostedRoundsAttr, strconv.Itoa(rounds)); err != nil {
+		booster.Free()
+		return nil, err
+	}
+	return booster, err
+}
+
+// trainContext run rounds iterations numbered from start and return the rounds of the
+// model, start plus the iterations run. modelRounds is the fixed round count of a
+// model updated in place, 0 when the iterations add rounds.
+func (booster *Booster) trainContext(ctx context.Context, dtrain *DMatrix, start int, rounds int, modelRounds int, opts TrainOptions) (int, error) {
+	info := &TrainInfo{Booster: booster, Rounds: start + rounds}
+	var (
+		data  []*DMatrix
+		names []string
+	)
+	for _, eval := range opts.Evals {
+		data = append(data, eval.Data)
+		names = append(names, eval.Name)
+	}
+	// fire call every callback, reporting whether one asked to stop
+	fire := func(call func(Callback) error) (bool, error) {
+		stop := false
+		for _, c := range opts.Callbacks {
+			switch err := call(c); err {
+			case nil:
+			case ErrStopTraining:
+				stop = true
+			default:
+				return false, fmt.Errorf("round %d: %v", info.Iteration, err)
+			}
+		}
+		return stop, nil
+	}
+
+	for iter := start; iter < start+rounds; iter++ {
+		if err := ctx.Err(); err != nil {
+			return iter, err
+		}
+		info.Iteration, info.ModelRounds, info.Evals = iter, iter+1, nil
+		if modelRounds > 0 {
+			info.ModelRounds = modelRounds
+		}
+		if stop, err := fire(func(c Callback) error { return c.BeforeIteration(info) }); stop || err != nil {
+			return iter, err
+		}
+		if err := booster.UpdateOneIter(iter, dtrain); err != nil {
+			return iter, fmt.Errorf("round %d: %v", iter, err)
+		}
+		if stop, err := fire(func(c Callback) error { return c.AfterIteration(info) }); stop || err != nil {
+			return iter + 1, err
+		}
+		if len(data) > 0 {
+			result, err := booster.EvalOneIter(iter, data, names)
+			if err != nil {
+				return iter + 1, fmt.Errorf("round %d: %v", iter, err)
+			}
+			if info.Evals, err = ParseEvalResult(result, names); err != nil {
+				return iter + 1, fmt.Errorf("round %d: %v", iter, err)
+			}
+		}
+		if stop, err := fire(func(c Callback) error { return c.AfterEvaluation(info) }); stop || err != nil {
+			return iter + 1, err
+		}
+	}
+	return start + rounds, nil
+}
+
+// Checkpoint save the model every n rounds and after the last round, so that
+// ContinueTraining can resume it. A %d in path is replaced by the rounds in the
+// model, and the file is replaced atomically.
+func Checkpoint(path string, every int) Callback {
+	return CallbackFuncs{After: func(info *TrainInfo) error {
+		done := info.Iteration + 1
+		if every > 0 && done%every != 0 && done != info.Rounds {
+			return nil
+		}
+		rounds := strconv.Itoa(info.ModelRounds)
+		if err := info.Booster.SetAttr(boostedRoundsAttr, rounds); err != nil {
+			return err
+		}
+		target := strings.Replace(path, "%d", rounds, -1)
+		tmp := filepath.Join(filepath.Dir(target), "."+filepath.Base(target)+".tmp")
+		if err := info.Booster.SaveModel(tmp); err != nil {
+			return fmt.Errorf("checkpoint: %v", err)
+		}
+		if err := os.Rename(tmp, target); err != nil {
+			os.Remove(tmp)
+			return fmt.Errorf("checkpoint: %v", err)
+		}
+		return nil
+	}}
+}
+
+// LearningRate set eta before every round to schedule(iteration)
+func LearningRate(schedule func(iteration int) float64) Callback {
+	return CallbackFuncs{Before: func(info *TrainInfo) error {
+		eta := schedule(info.Iteration)
+		return info.Booster.SetParam("eta", strconv.FormatFloat(eta, 'g', -1, 64))
+	}}
+}
+
+// Progress print every n-th round, the last one and its evaluation results to w, e.g.
+//
+//	[10/200]	train-rmse:0.3512	test-rmse:0.4120	1.52s
+func Progress(w io.Writer, every int) Callback {
+	var start time.Time
+	return CallbackFuncs{
+		Before: func(info *TrainInfo) error {
+			if info.Iteration == 0 || start.IsZero() {
+				start = time.Now()
+			}
+			return nil
+		},
+		AfterEval: func(info *TrainInfo) error {
+			done := info.Iteration + 1
+			if every > 0 && done%every != 0 && done != info.Rounds {
+				return nil
+			}
+			fields := []string{fmt.Sprintf("[%d/%d]", done, info.Rounds)}
+			for _, e := range info.Evals {
+				fields = append(fields, fmt.Sprintf("%s:%g", e.Name(), e.Value))
+			}
+			fields = append(fields, time.Since(start).Round(10*time.Millisecond).String())
+			_, err := fmt.Fprintln(w, strings.Join(fields, "\t"))
+			return err
+		},
+	}
+}
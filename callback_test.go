@@ -0,0 +1,251 @@
+package xgboost
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestTrainContextCallbacks(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	params := Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"}
+
+	var events []string
+	record := CallbackFuncs{
+		Before: func(info *TrainInfo) error {
+			events = append(events, "before "+strconv.Itoa(info.Iteration))
+			return nil
+		},
+		After: func(info *TrainInfo) error {
+			events = append(events, "after "+strconv.Itoa(info.Iteration))
+			return nil
+		},
+		AfterEval: func(info *TrainInfo) error {
+			events = append(events, "eval "+strconv.Itoa(info.Iteration)+" "+info.Evals[0].Data+" "+info.Evals[0].Metric)
+			if info.Iteration == 1 {
+				return ErrStopTraining
+			}
+			return nil
+		},
+	}
+	// a dashed eval name is split from its metric
+	opts := TrainOptions{Evals: []EvalSet{{"train-set", dm}}, Callbacks: []Callback{record}}
+	booster, err := TrainContext(context.Background(), params, dm, 10, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	want := []string{"before 0", "after 0", "eval 0 train-set rmse", "before 1", "after 1", "eval 1 train-set rmse"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("got events %q, expected %q", events, want)
+	}
+	if rounds, err := booster.BoostedRounds(params); err != nil || rounds != 2 {
+		t.Errorf("got %d boosted rounds, %v; expected 2", rounds, err)
+	}
+
+	failing := CallbackFuncs{After: func(*TrainInfo) error { return errors.New("disk full") }}
+	if _, err := TrainContext(context.Background(), params, dm, 10, TrainOptions{Callbacks: []Callback{failing}}); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("got %v, expected the callback error", err)
+	}
+}
+
+func TestTrainContextCancel(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	params := Params{"objective": "reg:linear", "silent": "1"}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stopAfter3 := CallbackFuncs{After: func(info *TrainInfo) error {
+		if info.Iteration == 2 {
+			cancel()
+		}
+		return nil
+	}}
+	booster, err := TrainContext(ctx, params, dm, 10, TrainOptions{Callbacks: []Callback{stopAfter3}})
+	if err != context.Canceled {
+		t.Fatalf("got %v, expected %v", err, context.Canceled)
+	}
+	defer booster.Free()
+	if rounds, err := booster.BoostedRounds(params); err != nil || rounds != 3 {
+		t.Errorf("got %d boosted rounds, %v; expected 3", rounds, err)
+	}
+}
+
+func TestBuiltinCallbacks(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	dir, err := ioutil.TempDir("", "xgboost-callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// only %d is replaced, other percent signs in the path are kept
+	dir = filepath.Join(dir, "sample-50%")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress bytes.Buffer
+	var etas []float64
+	schedule := func(iteration int) float64 {
+		eta := 0.3 / float64(iteration+1)
+		etas = append(etas, eta)
+		return eta
+	}
+	params := Params{"objective": "reg:linear", "max_depth": "2", "silent": "1"}
+	opts := TrainOptions{
+		Evals: []EvalSet{{"train", dm}},
+		Callbacks: []Callback{
+			LearningRate(schedule),
+			Checkpoint(filepath.Join(dir, "model-%d.bin"), 2),
+			Progress(&progress, 2),
+		},
+	}
+	booster, err := TrainContext(context.Background(), params, dm, 5, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+
+	if len(etas) != 5 || math.Abs(etas[4]-0.06) > 1e-12 {
+		t.Errorf("got learning rates %v", etas)
+	}
+	lines := strings.Split(strings.TrimSpace(progress.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "[2/5]\ttrain-rmse:") || !strings.HasPrefix(lines[2], "[5/5]\t") {
+		t.Errorf("unexpected progress:\n%s", progress.String())
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "model-2.bin"), filepath.Join(dir, "model-4.bin"), filepath.Join(dir, "model-5.bin")}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("got checkpoints %v, expected %v", files, want)
+	}
+	raw, err := ioutil.ReadFile(want[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var iterations []int
+	record := CallbackFuncs{AfterEval: func(info *TrainInfo) error {
+		iterations = append(iterations, info.Iteration)
+		if info.Rounds != 5 || len(info.Evals) != 1 {
+			t.Errorf("resumed round %d of %d with evals %v", info.Iteration, info.Rounds, info.Evals)
+		}
+		return nil
+	}}
+	resumed, err := ContinueTrainingContext(context.Background(), raw, dm, 3, params, TrainOptions{Evals: opts.Evals, Callbacks: []Callback{record}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Free()
+	if rounds, err := resumed.BoostedRounds(params); err != nil || rounds != 5 {
+		t.Errorf("resumed checkpoint has %d boosted rounds, %v; expected 5", rounds, err)
+	}
+	if !reflect.DeepEqual(iterations, []int{2, 3, 4}) {
+		t.Errorf("resumed iterations %v, expected [2 3 4]", iterations)
+	}
+}
+
+func TestLearningRateEffect(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	params := Params{"objective": "reg:linear", "max_depth": "2", "silent": "1"}
+	// nothing is learned after the first round with a learning rate of 0
+	frozen := LearningRate(func(iteration int) float64 {
+		if iteration == 0 {
+			return 0.3
+		}
+		return 0
+	})
+	booster, err := TrainContext(context.Background(), params, dm, 4, TrainOptions{Callbacks: []Callback{frozen}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	first, err := booster.Predict(dm, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := booster.Predict(dm, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := false
+	for i := range all {
+		if all[i] != first[i] {
+			t.Fatalf("row %d: %v after 4 rounds, %v after the first", i, all[i], first[i])
+		}
+		changed = changed || first[i] != 0.5
+	}
+	if !changed {
+		t.Error("the first round learned nothing")
+	}
+}
+
+func TestCheckpointUpdate(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	dir, err := ioutil.TempDir("", "xgboost-callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	params := Params{"objective": "reg:linear", "max_depth": "2", "silent": "1"}
+	booster, err := Train(params, dm, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// refreshing the first 2 rounds leaves a model of 4 rounds at every checkpoint
+	refresh := Params{
+		"objective":    "reg:linear",
+		"process_type": "update",
+		"updater":      "refresh",
+		"refresh_leaf": "1",
+		"silent":       "1",
+	}
+	path := filepath.Join(dir, "model-%d.bin")
+	opts := TrainOptions{Callbacks: []Callback{Checkpoint(path, 1)}}
+	refreshed, err := ContinueTrainingContext(context.Background(), raw, dm, 2, refresh, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer refreshed.Free()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(dir, "model-4.bin")
+	if !reflect.DeepEqual(files, []string{want}) {
+		t.Fatalf("got checkpoints %v, expected %v", files, want)
+	}
+	checkpoint, err := BoosterCreate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checkpoint.Free()
+	if err := checkpoint.LoadModel(want); err != nil {
+		t.Fatal(err)
+	}
+	if rounds, err := checkpoint.BoostedRounds(params); err != nil || rounds != 4 {
+		t.Errorf("checkpoint has %d boosted rounds, %v; expected 4", rounds, err)
+	}
+}
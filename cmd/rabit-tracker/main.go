@@ -0,0 +1,79 @@
+// Command rabit-tracker runs a rabit tracker for a distributed job. Given a command,
+// it also starts that many local copies of it as workers:
+//
+//	rabit-tracker -n 4 -- ./train -data part
+//
+// Without a command it prints the environment workers need and waits for them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go/tracker"
+)
+
+func main() {
+	var (
+		numWorkers = flag.Int("n", 1, "number of workers")
+		host       = flag.String("host", "127.0.0.1", "address to listen on")
+		port       = flag.Int("port", 0, "port to listen on, 0 picks a free one")
+	)
+	flag.Parse()
+
+	t, err := tracker.New(fmt.Sprintf("%s:%d", *host, *port), *numWorkers)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	t.Print = func(msg string) {
+		fmt.Print(msg)
+		if !strings.HasSuffix(msg, "\n") {
+			fmt.Println()
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Run()
+	}()
+
+	if flag.NArg() == 0 {
+		log.Printf("tracker listening, start workers with: %s", strings.Join(t.WorkerEnv(), " "))
+		if err := <-done; err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+
+	workers := make([]*exec.Cmd, *numWorkers)
+	for i := range workers {
+		cmd := exec.Command(flag.Arg(0), flag.Args()[1:]...)
+		cmd.Env = append(os.Environ(), t.WorkerEnv()...)
+		cmd.Env = append(cmd.Env, "DMLC_TASK_ID="+strconv.Itoa(i), "DMLC_ROLE=worker")
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			log.Fatalln(err)
+		}
+		workers[i] = cmd
+	}
+	failed := false
+	for i, cmd := range workers {
+		if err := cmd.Wait(); err != nil {
+			log.Printf("worker %d: %v", i, err)
+			failed = true
+		}
+	}
+	if failed {
+		t.Close()
+		os.Exit(1)
+	}
+	if err := <-done; err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,86 @@
+// Command xgb-codegen compiles an xgboost model into standalone source code, for use
+// with go generate:
+//
+//	//go:generate xgb-codegen -model churn.bin -func PredictChurn -o churn_model.go
+//
+// Under go generate the package name defaults to that of the file with the directive.
+// With -lang c, a C99 source is written to -o and its header next to it:
+//
+//	xgb-codegen -model churn.bin -lang c -func churn_predict -o churn_model.c
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/codegen"
+)
+
+func main() {
+	var (
+		modelPath = flag.String("model", "", "model file")
+		lang      = flag.String("lang", "go", "language of the generated source, go or c")
+		pkg       = flag.String("package", os.Getenv("GOPACKAGE"), "package name of generated Go source")
+		funcName  = flag.String("func", "", "name of the prediction function, Predict for Go and predict for C by default")
+		output    = flag.String("o", "", "file to write, stdout by default")
+	)
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("xgb-codegen: ")
+	if *modelPath == "" {
+		log.Fatalln("no model given, use -model")
+	}
+
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer booster.Free()
+	if err := booster.LoadModel(*modelPath); err != nil {
+		log.Fatalf("load %s: %v", *modelPath, err)
+	}
+	m, err := booster.TreeModel()
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	command := "xgb-codegen " + strings.Join(os.Args[1:], " ")
+	var src, header bytes.Buffer
+	headerPath := ""
+	switch *lang {
+	case "go":
+		err = codegen.GenerateGo(&src, m, codegen.GoOptions{Package: *pkg, FuncName: *funcName, Command: command})
+	case "c":
+		if *output == "" {
+			log.Fatalln("no source file given for C, use -o")
+		}
+		headerPath = strings.TrimSuffix(*output, filepath.Ext(*output)) + ".h"
+		opts := codegen.COptions{FuncName: *funcName, Header: filepath.Base(headerPath), Command: command}
+		err = codegen.GenerateC(&header, &src, m, opts)
+	default:
+		err = fmt.Errorf("unknown language %q", *lang)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(src.Bytes())
+		return
+	}
+	if headerPath != "" {
+		if err := ioutil.WriteFile(headerPath, header.Bytes(), 0644); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if err := ioutil.WriteFile(*output, src.Bytes(), 0644); err != nil {
+		log.Fatalln(err)
+	}
+}
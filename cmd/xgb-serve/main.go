@@ -0,0 +1,151 @@
+// Command xgb-serve serves predictions of xgboost models over HTTP, and over gRPC
+// when -grpc-addr is given:
+//
+//	xgb-serve -addr :8080 -grpc-addr :9090 -model churn=churn.bin -model fraud=fraud.bin
+//
+// Models are reloaded when their file changes. See serving.Handler for the HTTP
+// endpoints and rpc/predictpb for the gRPC service.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/liuhaoXD/xgboost-go/rpc/server"
+	"github.com/liuhaoXD/xgboost-go/serving"
+)
+
+// modelFlags collect repeated -model name=path flags
+type modelFlags []string
+
+func (f *modelFlags) String() string     { return strings.Join(*f, ",") }
+func (f *modelFlags) Set(v string) error { *f = append(*f, v); return nil }
+
+func main() {
+	var models modelFlags
+	flag.Var(&models, "model", "model to serve as name=path, or path to name it after the file; repeatable")
+	var (
+		addr           = flag.String("addr", ":8080", "address to listen on")
+		grpcAddr       = flag.String("grpc-addr", "", "address to serve gRPC on, empty disables gRPC")
+		batchRows      = flag.Int("batch-rows", 64, "largest number of rows predicted in one batch, 1 disables batching")
+		batchWait      = flag.Duration("batch-wait", 2*time.Millisecond, "longest time a request waits for its batch to fill")
+		reloadInterval = flag.Duration("reload-interval", 10*time.Second, "how often model files are checked for changes, 0 disables reloading")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s -model name=path [-model ...] [flags]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if len(models) == 0 {
+		log.Fatalln("no model given, use -model name=path")
+	}
+
+	store := serving.NewStore()
+	for _, m := range models {
+		name, path := modelName(m)
+		if err := store.Load(name, path); err != nil {
+			log.Fatalln(err)
+		}
+		log.Printf("loaded model %s from %s", name, path)
+	}
+
+	stop := make(chan struct{})
+	if *reloadInterval > 0 {
+		go store.Watch(*reloadInterval, stop)
+	}
+
+	batcher := &serving.Batcher{MaxRows: *batchRows, MaxWait: *batchWait}
+	httpServer := &http.Server{
+		Addr:    *addr,
+		Handler: &serving.Handler{Store: store, Batcher: batcher},
+	}
+
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		grpcServer = grpc.NewServer()
+		(&server.Server{Store: store, Batcher: batcher}).Register(grpcServer)
+		go func() {
+			log.Printf("serving gRPC on %s", *grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalln(err)
+			}
+		}()
+	}
+
+	// on a signal both servers drain their requests concurrently; done is closed once
+	// they have, so main never returns while a prediction still uses the store
+	done := make(chan struct{})
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		log.Println("shutting down")
+		close(stop)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := httpServer.Shutdown(ctx); err != nil {
+				log.Printf("http shutdown: %v", err)
+			}
+		}()
+		if grpcServer != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				stopped := make(chan struct{})
+				go func() {
+					grpcServer.GracefulStop()
+					close(stopped)
+				}()
+				select {
+				case <-stopped:
+				case <-ctx.Done():
+					log.Printf("grpc shutdown: %v", ctx.Err())
+					grpcServer.Stop()
+					<-stopped
+				}
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalln(err)
+	}
+	<-done
+	if err := store.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// modelName split a -model flag into model name and path
+func modelName(flagValue string) (string, string) {
+	if i := strings.Index(flagValue, "="); i > 0 {
+		return flagValue[:i], flagValue[i+1:]
+	}
+	base := filepath.Base(flagValue)
+	return strings.TrimSuffix(base, filepath.Ext(base)), flagValue
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+func runDiagnose(args []string) error {
+	fs, format := newFlagSet("diagnose")
+	var features features
+	features.register(fs)
+	var (
+		model  = fs.String("model", "", "model file")
+		bins   = fs.Int("bins", 0, "print split threshold histograms with this many bins, 0 omits them")
+		strict = fs.Bool("strict", false, "fail when the model has anomalies")
+	)
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	names, _, err := features.load()
+	if err != nil {
+		return err
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	m, err := booster.TreeModel()
+	if err != nil {
+		return err
+	}
+	d := m.Diagnose()
+
+	if *format == "json" {
+		if err := writeJSON(os.Stdout, d); err != nil {
+			return err
+		}
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(w, "num_trees\t%d\n", d.NumTrees)
+		for depth, count := range d.DepthCounts {
+			if count > 0 {
+				fmt.Fprintf(w, "trees of depth %d\t%d\n", depth, count)
+			}
+		}
+		leaves := 0
+		for _, tree := range d.Trees {
+			leaves += tree.Leaves
+		}
+		if d.NumTrees > 0 {
+			fmt.Fprintf(w, "mean leaves per tree\t%.2f\n", float64(leaves)/float64(d.NumTrees))
+		}
+		used := make([]int, 0, len(d.Features))
+		for feature := range d.Features {
+			used = append(used, feature)
+		}
+		sort.Ints(used)
+		for _, feature := range used {
+			f := d.Features[feature]
+			fmt.Fprintf(w, "feature %s\t%d splits at %d thresholds, gain %g\n", featureName(names, feature), f.Splits, len(f.Thresholds), f.Gain)
+			if *bins > 0 {
+				for _, bin := range f.Histogram(*bins) {
+					fmt.Fprintf(w, "\t[%g, %g]\t%d\n", bin.Min, bin.Max, bin.Count)
+				}
+			}
+		}
+		dead := make([]string, len(d.DeadFeatures))
+		for i, feature := range d.DeadFeatures {
+			dead[i] = featureName(names, feature)
+		}
+		fmt.Fprintf(w, "dead features\t%s\n", strings.Join(dead, ","))
+		for _, a := range d.Anomalies {
+			fmt.Fprintf(w, "anomaly %s\t%s\n", a.Kind, a.Message)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	if *strict && len(d.Anomalies) > 0 {
+		return fmt.Errorf("%d anomalies", len(d.Anomalies))
+	}
+	return nil
+}
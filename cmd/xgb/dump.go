@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func runDump(args []string) error {
+	fs, format := newFlagSet("dump")
+	var features features
+	features.register(fs)
+	var (
+		model = fs.String("model", "", "model file")
+		stats = fs.Bool("stats", false, "include the gain and cover of every node")
+	)
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	names, types, err := features.load()
+	if err != nil {
+		return err
+	}
+	var trees []string
+	if features.names != "" {
+		trees, err = booster.DumpModelExWithFeatures(len(names), names, types, *stats, *format)
+	} else {
+		trees, err = booster.DumpModelEx(features.fmap, *stats, *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		dumped := make([]json.RawMessage, len(trees))
+		for i, tree := range trees {
+			dumped[i] = json.RawMessage(tree)
+		}
+		return writeJSON(os.Stdout, dumped)
+	}
+	for i, tree := range trees {
+		fmt.Printf("booster[%d]:\n%s", i, tree)
+	}
+	return nil
+}
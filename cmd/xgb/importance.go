@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+type featureImportance struct {
+	Feature    string  `json:"feature"`
+	Importance float64 `json:"importance"`
+}
+
+func runImportance(args []string) error {
+	fs, format := newFlagSet("importance")
+	var features features
+	features.register(fs)
+	var (
+		model          = fs.String("model", "", "model file")
+		importanceType = fs.String("type", "weight", "weight, gain, cover, total_gain or total_cover")
+	)
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	names, _, err := features.load()
+	if err != nil {
+		return err
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	scores, err := booster.FeatureImportance(*importanceType)
+	if err != nil {
+		return err
+	}
+	list := make([]featureImportance, 0, len(scores))
+	for index, score := range scores {
+		list = append(list, featureImportance{featureName(names, index), score})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Importance != list[j].Importance {
+			return list[i].Importance > list[j].Importance
+		}
+		return list[i].Feature < list[j].Feature
+	})
+
+	if *format == "json" {
+		return writeJSON(os.Stdout, list)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "feature\t%s\n", *importanceType)
+	for _, f := range list {
+		fmt.Fprintf(w, "%s\t%g\n", f.Feature, f.Importance)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+type modelInfo struct {
+	Path       string            `json:"path"`
+	Objective  string            `json:"objective"`
+	Booster    string            `json:"booster"`
+	NumFeature int               `json:"num_feature"`
+	NumClass   int               `json:"num_class"`
+	BaseMargin float32           `json:"base_margin"`
+	NumTrees   int               `json:"num_trees"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+func runInfo(args []string) error {
+	fs, format := newFlagSet("info")
+	model := fs.String("model", "", "model file")
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	params, err := booster.LearnerParams()
+	if err != nil {
+		return err
+	}
+	trees, err := booster.DumpModel("", false)
+	if err != nil {
+		return err
+	}
+	info := modelInfo{
+		Path:       *model,
+		Objective:  params.Objective,
+		Booster:    params.Booster,
+		NumFeature: params.NumFeature,
+		NumClass:   params.NumClass,
+		BaseMargin: params.BaseScore,
+		NumTrees:   len(trees),
+		Attributes: map[string]string{},
+	}
+	names, err := booster.GetAttrNames()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if info.Attributes[name], err = booster.GetAttr(name); err != nil {
+			return err
+		}
+	}
+
+	if *format == "json" {
+		return writeJSON(os.Stdout, info)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "path\t%s\n", info.Path)
+	fmt.Fprintf(w, "objective\t%s\n", info.Objective)
+	fmt.Fprintf(w, "booster\t%s\n", info.Booster)
+	fmt.Fprintf(w, "num_feature\t%d\n", info.NumFeature)
+	fmt.Fprintf(w, "num_class\t%d\n", info.NumClass)
+	fmt.Fprintf(w, "base_margin\t%g\n", info.BaseMargin)
+	fmt.Fprintf(w, "num_trees\t%d\n", info.NumTrees)
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "attr %s\t%s\n", name, info.Attributes[name])
+	}
+	return w.Flush()
+}
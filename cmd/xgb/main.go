@@ -0,0 +1,189 @@
+// Command xgb trains xgboost models and inspects them:
+//
+//	xgb train -data train.libsvm -params train.conf -rounds 100 -eval test=test.libsvm -o model.bin
+//	xgb predict -model model.bin -data test.libsvm -margin
+//	xgb dump -model model.bin -fmap featmap.txt -stats
+//	xgb importance -model model.bin -type gain -features age,income,plan
+//	xgb info -model model.bin
+//	xgb diagnose -model model.bin -strict
+//	xgb prune -model model.bin -rounds 50 -o small.bin
+//	xgb plot -model model.bin -fmap featmap.txt -color gain -o model.html
+//
+// Every subcommand but plot prints text, or JSON with -format json; plot writes an HTML
+// page of the trees, or a Graphviz DOT graph of one tree with -format dot. Run
+// "xgb <command> -h" for the flags of a command.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"train", "train a model on a data file", runTrain},
+	{"predict", "predict a data file with a model", runPredict},
+	{"dump", "dump the trees of a model", runDump},
+	{"importance", "print the feature importance of a model", runImportance},
+	{"info", "print the parameters and attributes of a model", runInfo},
+	{"prune", "save a model with fewer trees", runPrune},
+	{"diagnose", "print statistics of the trees of a model and flag anomalies", runDiagnose},
+	{"plot", "render the trees of a model as html or graphviz dot", runPlot},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s <command> [flags]\n\ncommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == os.Args[1] {
+			if err := c.run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %s: %v\n", os.Args[0], c.name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+// newFlagSet create the flag set of a command with the -format flag every command has
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	format := fs.String("format", "text", "output format, text or json")
+	return fs, format
+}
+
+func checkFormat(format string) error {
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// listFlag collect a repeated flag
+type listFlag []string
+
+func (f *listFlag) String() string     { return strings.Join(*f, ",") }
+func (f *listFlag) Set(v string) error { *f = append(*f, v); return nil }
+
+// splitPair split a name=value flag
+func splitPair(flagValue string) (string, string, error) {
+	i := strings.Index(flagValue, "=")
+	if i <= 0 {
+		return "", "", fmt.Errorf("expected name=value, got %q", flagValue)
+	}
+	return flagValue[:i], flagValue[i+1:], nil
+}
+
+func loadBooster(path string) (*xgboost.Booster, error) {
+	if path == "" {
+		return nil, errors.New("no model given, use -model")
+	}
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := booster.LoadModel(path); err != nil {
+		booster.Free()
+		return nil, fmt.Errorf("load %s: %v", path, err)
+	}
+	return booster, nil
+}
+
+func loadData(path string) (*xgboost.DMatrix, error) {
+	if path == "" {
+		return nil, errors.New("no data given, use -data")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return xgboost.DMatrixCreateFromFile(path, 1)
+}
+
+// features are feature names and types, from a feature map file or a list of names
+type features struct {
+	fmap  string
+	names string
+}
+
+func (f *features) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.fmap, "fmap", "", "feature map file, with lines of <index> <name> <type>")
+	fs.StringVar(&f.names, "features", "", "comma separated feature names, instead of -fmap")
+}
+
+// load get the feature names and types, both nil when neither flag is given
+func (f *features) load() ([]string, []string, error) {
+	if f.fmap != "" && f.names != "" {
+		return nil, nil, errors.New("-fmap and -features are exclusive")
+	}
+	if f.names != "" {
+		names := strings.Split(f.names, ",")
+		types := make([]string, len(names))
+		for i := range types {
+			types[i] = "q"
+		}
+		return names, types, nil
+	}
+	if f.fmap == "" {
+		return nil, nil, nil
+	}
+	data, err := ioutil.ReadFile(f.fmap)
+	if err != nil {
+		return nil, nil, err
+	}
+	var names, types []string
+	for n, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, nil, fmt.Errorf("%s:%d: expected <index> <name> <type>", f.fmap, n+1)
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil || index != len(names) {
+			return nil, nil, fmt.Errorf("%s:%d: expected feature index %d", f.fmap, n+1, len(names))
+		}
+		names = append(names, fields[1])
+		types = append(types, fields[2])
+	}
+	return names, types, nil
+}
+
+// featureName get the name of feature index, or "f<index>" without a name
+func featureName(names []string, index int) string {
+	if index < len(names) {
+		return names[index]
+	}
+	return "f" + strconv.Itoa(index)
+}
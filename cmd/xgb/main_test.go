@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFeaturesLoad(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	if names, types, err := (&features{}).load(); names != nil || types != nil || err != nil {
+		t.Errorf("got %v %v %v without flags", names, types, err)
+	}
+
+	names, types, err := (&features{names: "age,income,smoker"}).load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"age", "income", "smoker"}) || !reflect.DeepEqual(types, []string{"q", "q", "q"}) {
+		t.Errorf("got names %v types %v", names, types)
+	}
+
+	fmap := filepath.Join(dir, "features.fmap")
+	writeFile(t, fmap, "0 age q\n1 income float\n\n2 smoker i\n")
+	names, types, err = (&features{fmap: fmap}).load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"age", "income", "smoker"}) || !reflect.DeepEqual(types, []string{"q", "float", "i"}) {
+		t.Errorf("got names %v types %v", names, types)
+	}
+
+	for name, content := range map[string]string{
+		"fields.fmap": "0 age\n",
+		"order.fmap":  "0 age q\n2 income q\n",
+	} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+		if _, _, err := (&features{fmap: path}).load(); err == nil {
+			t.Errorf("expected an error loading %s", name)
+		}
+	}
+	if _, _, err := (&features{fmap: fmap, names: "age"}).load(); err == nil {
+		t.Error("expected an error with both -fmap and -features")
+	}
+}
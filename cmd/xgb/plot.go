@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/liuhaoXD/xgboost-go/plot"
+)
+
+func runPlot(args []string) error {
+	// plot writes DOT or HTML rather than text or json, so it has its own -format
+	fs := flag.NewFlagSet("plot", flag.ExitOnError)
+	var features features
+	features.register(fs)
+	var (
+		model  = fs.String("model", "", "model file")
+		format = fs.String("format", "html", "output format, dot for one tree or html for every tree")
+		tree   = fs.Int("tree", 0, "index of the tree to render as dot")
+		stats  = fs.Bool("stats", false, "show the gain and cover of every node")
+		color  = fs.String("color", "", "shade nodes by gain or cover")
+		title  = fs.String("title", "", "title of the html page, the model file by default")
+		output = fs.String("o", "", "file to write, stdout by default")
+	)
+	fs.Parse(args)
+	if *format != "dot" && *format != "html" {
+		return fmt.Errorf("unknown format %q, expected dot or html", *format)
+	}
+	names, _, err := features.load()
+	if err != nil {
+		return err
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	opts := plot.Options{FeatureNames: names, Stats: *stats, Color: *color, Title: *title}
+	if opts.Title == "" {
+		opts.Title = *model
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "dot" {
+		dot, err := booster.TreeToDOT(*tree, opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, dot)
+		return err
+	}
+	return booster.TreesToHTML(w, opts)
+}
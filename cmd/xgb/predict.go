@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+func runPredict(args []string) error {
+	fs, format := newFlagSet("predict")
+	var (
+		model      = fs.String("model", "", "model file")
+		data       = fs.String("data", "", "data file to predict")
+		margin     = fs.Bool("margin", false, "output the raw margin instead of the transformed prediction")
+		leaf       = fs.Bool("leaf", false, "output the leaf index of every tree")
+		contribs   = fs.Bool("contribs", false, "output the feature contributions, the bias last")
+		ntreeLimit = fs.Uint("ntree-limit", 0, "predict with only the first trees, 0 uses all")
+		output     = fs.String("o", "", "file to write the predictions to, stdout by default")
+	)
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	var mask int
+	for i, set := range []bool{*margin, *leaf, *contribs} {
+		if set {
+			if mask != 0 {
+				return fmt.Errorf("-margin, -leaf and -contribs are exclusive")
+			}
+			mask = 1 << uint(i)
+		}
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	dm, err := loadData(*data)
+	if err != nil {
+		return err
+	}
+	defer dm.Free()
+	preds, err := booster.Predict(dm, mask, *ntreeLimit)
+	if err != nil {
+		return err
+	}
+	numRow, err := dm.NumRow()
+	if err != nil {
+		return err
+	}
+	rows := make([][]float32, numRow)
+	if numRow > 0 {
+		perRow := len(preds) / int(numRow)
+		for i := range rows {
+			rows[i] = preds[i*perRow : (i+1)*perRow]
+		}
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		if out, err = os.Create(*output); err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+	if *format == "json" {
+		return writeJSON(out, rows)
+	}
+	w := bufio.NewWriter(out)
+	for _, row := range rows {
+		for j, v := range row {
+			if j > 0 {
+				w.WriteByte('\t')
+			}
+			w.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+func runPrune(args []string) error {
+	fs, format := newFlagSet("prune")
+	var (
+		model   = fs.String("model", "", "model file")
+		rounds  = fs.Int("rounds", 0, "keep the trees of the first rounds, 0 keeps every round")
+		minGain = fs.Float64("min-gain", 0, "drop the trees whose splits gain less than this in total")
+		output  = fs.String("o", "", "file to save the pruned model to")
+	)
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if *output == "" {
+		return errors.New("no output file given, use -o")
+	}
+
+	booster, err := loadBooster(*model)
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	before, err := booster.LearnerParams()
+	if err != nil {
+		return err
+	}
+	truncated, err := booster.Truncate(*rounds)
+	if err != nil {
+		return err
+	}
+	defer truncated.Free()
+	pruned := truncated
+	if *minGain > 0 {
+		if pruned, err = truncated.PruneTrees(*minGain); err != nil {
+			return err
+		}
+		defer pruned.Free()
+	}
+	after, err := pruned.LearnerParams()
+	if err != nil {
+		return err
+	}
+	if err := pruned.SaveModel(*output); err != nil {
+		return err
+	}
+
+	result := struct {
+		Trees  int    `json:"trees"`
+		Kept   int    `json:"kept"`
+		Output string `json:"output"`
+	}{len(before.TreeGroups), len(after.TreeGroups), *output}
+	if *format == "json" {
+		return writeJSON(os.Stdout, result)
+	}
+	fmt.Printf("kept %d of %d trees in %s\n", result.Kept, result.Trees, result.Output)
+	return nil
+}
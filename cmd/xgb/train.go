@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+func runTrain(args []string) error {
+	fs, format := newFlagSet("train")
+	var sets, evals listFlag
+	var (
+		data            = fs.String("data", "", "training data file")
+		paramsFile      = fs.String("params", "", "params file, JSON or lines of key = value")
+		rounds          = fs.Int("rounds", 10, "number of boosting rounds")
+		earlyStopping   = fs.Int("early-stopping", 0, "stop when the last eval set has not improved for this many rounds, 0 disables")
+		metric          = fs.String("metric", "", "metric for early stopping, the last one evaluated by default")
+		output          = fs.String("o", "xgb.model", "file to save the model to")
+		checkpoint      = fs.String("checkpoint", "", "file to save checkpoints to, a %d in it is replaced by the round")
+		checkpointEvery = fs.Int("checkpoint-every", 10, "rounds between checkpoints")
+	)
+	fs.Var(&sets, "set", "param as key=value, overriding the params file; repeatable")
+	fs.Var(&evals, "eval", "eval set as name=file; repeatable")
+	fs.Parse(args)
+	if err := checkFormat(*format); err != nil {
+		return err
+	}
+	if *earlyStopping > 0 && len(evals) == 0 {
+		return errors.New("-early-stopping needs an -eval set")
+	}
+
+	params := xgboost.Params{}
+	if *paramsFile != "" {
+		var err error
+		if params, err = readParams(*paramsFile); err != nil {
+			return err
+		}
+	}
+	for _, set := range sets {
+		key, value, err := splitPair(set)
+		if err != nil {
+			return err
+		}
+		params[key] = value
+	}
+
+	dtrain, err := loadData(*data)
+	if err != nil {
+		return err
+	}
+	defer dtrain.Free()
+	var opts xgboost.TrainOptions
+	for _, eval := range evals {
+		name, path, err := splitPair(eval)
+		if err != nil {
+			return err
+		}
+		dm, err := loadData(path)
+		if err != nil {
+			return err
+		}
+		defer dm.Free()
+		opts.Evals = append(opts.Evals, xgboost.EvalSet{Name: name, Data: dm})
+	}
+
+	stopper := &earlyStopper{rounds: *earlyStopping, metric: *metric}
+	report := xgboost.CallbackFuncs{AfterEval: func(info *xgboost.TrainInfo) error {
+		if len(info.Evals) == 0 {
+			return nil
+		}
+		if *format == "json" {
+			values := map[string]float64{}
+			for _, e := range info.Evals {
+				values[e.Name()] = e.Value
+			}
+			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"iteration": info.Iteration, "eval": values})
+		} else {
+			fields := []string{fmt.Sprintf("[%d]", info.Iteration)}
+			for _, e := range info.Evals {
+				fields = append(fields, fmt.Sprintf("%s:%g", e.Name(), e.Value))
+			}
+			fmt.Println(strings.Join(fields, "\t"))
+		}
+		if stopper.update(info.Iteration, info.Evals, opts.Evals[len(opts.Evals)-1].Name) {
+			return xgboost.ErrStopTraining
+		}
+		return nil
+	}}
+	opts.Callbacks = append(opts.Callbacks, report)
+	if *checkpoint != "" {
+		opts.Callbacks = append(opts.Callbacks, xgboost.Checkpoint(*checkpoint, *checkpointEvery))
+	}
+
+	// an interrupt stops training after the current round, keeping the rounds so far
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	booster, err := xgboost.TrainContext(ctx, params, dtrain, *rounds, opts)
+	if err != nil && err != ctx.Err() {
+		return err
+	}
+	defer booster.Free()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "training interrupted, saving the rounds boosted so far")
+	}
+
+	if stopper.started {
+		for key, value := range map[string]string{
+			"best_iteration": strconv.Itoa(stopper.best),
+			"best_score":     strconv.FormatFloat(stopper.score, 'g', -1, 64),
+		} {
+			if err := booster.SetAttr(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if err := booster.SaveModel(*output); err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		summary := map[string]interface{}{"model": *output}
+		if stopper.started {
+			summary["best_iteration"] = stopper.best
+			summary["best_score"] = stopper.score
+			summary["metric"] = stopper.metric
+		}
+		return writeJSON(os.Stdout, summary)
+	}
+	if stopper.started {
+		fmt.Printf("best iteration %d, %s %g\n", stopper.best, stopper.metric, stopper.score)
+	}
+	fmt.Printf("model saved to %s\n", *output)
+	return nil
+}
+
+// earlyStopper track the best round of a metric on the last eval set
+type earlyStopper struct {
+	rounds   int
+	metric   string
+	maximize bool
+	best     int
+	score    float64
+	started  bool
+}
+
+// update record the eval entries of round iter, it reports whether to stop training
+func (s *earlyStopper) update(iter int, entries []xgboost.EvalResult, data string) bool {
+	var entry *xgboost.EvalResult
+	for i := range entries {
+		if entries[i].Data == data && (s.metric == "" || entries[i].Metric == s.metric) {
+			entry = &entries[i]
+		}
+	}
+	if entry == nil {
+		return false
+	}
+	if !s.started {
+		s.started = true
+		s.metric = entry.Metric
+		s.maximize = xgboost.MetricMaximize(entry.Metric)
+		s.best, s.score = iter, entry.Value
+		return false
+	}
+	if (s.maximize && entry.Value > s.score) || (!s.maximize && entry.Value < s.score) {
+		s.best, s.score = iter, entry.Value
+	}
+	return s.rounds > 0 && iter-s.best >= s.rounds
+}
+
+// readParams read a params file, a JSON object when it ends in .json and lines of
+// key = value with # comments otherwise
+func readParams(path string) (xgboost.Params, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	params := xgboost.Params{}
+	if filepath.Ext(path) == ".json" {
+		// numbers keep their text, 1000000 would print as 1e+06 from a float64
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var values map[string]interface{}
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		for key, value := range values {
+			switch value := value.(type) {
+			case string:
+				params[key] = value
+			case json.Number:
+				params[key] = value.String()
+			case bool:
+				params[key] = strconv.FormatBool(value)
+			default:
+				return nil, fmt.Errorf("%s: %s must be a string, number or boolean", path, key)
+			}
+		}
+		return params, nil
+	}
+	for n, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%s:%d: expected key = value", path, n+1)
+		}
+		params[strings.TrimSpace(line[:i])] = strings.TrimSpace(line[i+1:])
+	}
+	return params, nil
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "xgb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadParams(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	want := xgboost.Params{"objective": "binary:logistic", "eta": "0.1", "max_depth": "6", "lambda": "1e-05", "silent": "true", "seed": "1000000"}
+
+	json := filepath.Join(dir, "params.json")
+	writeFile(t, json, `{"objective": "binary:logistic", "eta": 0.1, "max_depth": 6, "lambda": 1e-05, "silent": true, "seed": 1000000}`)
+	params, err := readParams(json)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("JSON params %v, expected %v", params, want)
+	}
+
+	conf := filepath.Join(dir, "params.conf")
+	writeFile(t, conf, "# booster\nobjective = binary:logistic\neta=0.1   # step\n\n  max_depth = 6\nlambda = 1e-05\nsilent = true\nseed = 1000000\n")
+	params, err = readParams(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("key = value params %v, expected %v", params, want)
+	}
+
+	for name, content := range map[string]string{
+		"bad.json":    `{"eta": 0.1`,
+		"object.json": `{"eta": 0.1, "tree_method": {"name": "hist"}}`,
+		"array.json":  `{"eta": 0.1, "eval_metric": ["auc", "logloss"]}`,
+		"null.json":   `{"eta": null}`,
+		"bad.conf":    "eta = 0.1\nmax_depth 6\n",
+	} {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+		if _, err := readParams(path); err == nil {
+			t.Errorf("expected an error reading %s", name)
+		}
+	}
+	if _, err := readParams(filepath.Join(dir, "missing.conf")); err == nil {
+		t.Error("expected an error reading a missing file")
+	}
+}
+
+func TestEarlyStopper(t *testing.T) {
+	names := []string{"train", "valid-2020"}
+	round := func(iter int, result string) []xgboost.EvalResult {
+		entries, err := xgboost.ParseEvalResult(result, names)
+		if err != nil {
+			t.Fatalf("round %d: %v", iter, err)
+		}
+		return entries
+	}
+
+	// auc is maximized, on the dashed eval set rather than train
+	s := &earlyStopper{rounds: 2}
+	for iter, result := range []string{
+		"[0]\ttrain-auc:0.70\tvalid-2020-auc:0.70",
+		"[1]\ttrain-auc:0.80\tvalid-2020-auc:0.80",
+		"[2]\ttrain-auc:0.90\tvalid-2020-auc:0.75",
+	} {
+		if s.update(iter, round(iter, result), "valid-2020") {
+			t.Fatalf("stopped at round %d", iter)
+		}
+	}
+	if !s.update(3, round(3, "[3]\ttrain-auc:0.95\tvalid-2020-auc:0.79"), "valid-2020") {
+		t.Error("expected to stop two rounds after the best")
+	}
+	if !s.started || s.metric != "auc" || !s.maximize || s.best != 1 || s.score != 0.8 {
+		t.Errorf("unexpected state %+v", s)
+	}
+
+	// rmse is minimized, the chosen metric among several
+	s = &earlyStopper{metric: "rmse"}
+	for iter, result := range []string{
+		"[0]\tvalid-2020-rmse:1.0\tvalid-2020-auc:0.5",
+		"[1]\tvalid-2020-rmse:0.9\tvalid-2020-auc:0.6",
+		"[2]\tvalid-2020-rmse:0.95\tvalid-2020-auc:0.7",
+	} {
+		if s.update(iter, round(iter, result), "valid-2020") {
+			t.Fatalf("stopped at round %d without early stopping", iter)
+		}
+	}
+	if s.maximize || s.best != 1 || s.score != 0.9 {
+		t.Errorf("unexpected state %+v", s)
+	}
+
+	s = &earlyStopper{rounds: 1, metric: "logloss"}
+	if s.update(0, round(0, "[0]\tvalid-2020-rmse:1.0"), "valid-2020") || s.started {
+		t.Errorf("started without the metric %+v", s)
+	}
+}
@@ -0,0 +1,44 @@
+package codegen
+
+import (
+	"math"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testbooster"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+func TestGenerateBooster(t *testing.T) {
+	rows := testbooster.Rows
+	for _, c := range []struct {
+		objective string
+		numClass  int
+	}{
+		{"reg:linear", 1},
+		{"binary:logistic", 2},
+		{"count:poisson", 1},
+		{"binary:hinge", 2},
+		{"multi:softprob", 3},
+	} {
+		t.Run(c.objective, func(t *testing.T) {
+			m, want := testbooster.Train(t, c.objective, c.numClass)
+			perRow := len(want) / len(rows)
+			for lang, run := range map[string]func(*testing.T, *model.TreeModel, model.Matrix) ([]float32, [][]float32){"go": runGo, "c": runC} {
+				t.Run(lang, func(t *testing.T) {
+					preds, proba := run(t, m, rows)
+					for i := range rows {
+						got := []float32{preds[i]}
+						if perRow > 1 {
+							got = proba[i]
+						}
+						for j := range got {
+							if math.Abs(float64(got[j]-want[i*perRow+j])) > 1e-5 {
+								t.Fatalf("row %d: generated %v, booster %v", i, got, want[i*perRow:(i+1)*perRow])
+							}
+						}
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// COptions configure the generated C source
+type COptions struct {
+	// FuncName is the name of the prediction function, "predict" by default. Macros and
+	// helpers are named after it, so several models can be linked in one program.
+	FuncName string
+	// Header is the name the source includes the header by, "model.h" by default
+	Header string
+	// Command is mentioned in the generated code header
+	Command string
+}
+
+// GenerateC write a C99 header and source predicting with m, depending on nothing but
+// math.h. The header declares
+//
+//	float predict(const float *features);
+//
+// giving the prediction of Booster.Predict for a row of PREDICT_NUM_FEATURE features,
+// NaN features are missing. For multiclass models it gives the predicted class, and
+//
+//	void predict_proba(const float *features, float *probs);
+//
+// writes the probability of the PREDICT_NUM_CLASS classes.
+func GenerateC(header, source io.Writer, m *model.TreeModel, opts COptions) error {
+	t, err := objectiveTransform(m)
+	if err != nil {
+		return err
+	}
+	if opts.FuncName == "" {
+		opts.FuncName = "predict"
+	}
+	if opts.Header == "" {
+		opts.Header = "model.h"
+	}
+	if !isIdentifier(opts.FuncName) {
+		return fmt.Errorf("function name %q is not a C identifier", opts.FuncName)
+	}
+	name := opts.FuncName
+	macro := strings.ToUpper(name)
+	command := "xgb-codegen"
+	if opts.Command != "" {
+		command = opts.Command
+	}
+	generated := fmt.Sprintf("/* Code generated by %s. DO NOT EDIT. */\n\n", command)
+
+	var h bytes.Buffer
+	guard := strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, opts.Header)
+	h.WriteString(generated)
+	fmt.Fprintf(&h, "#ifndef %s_\n#define %s_\n\n", guard, guard)
+	h.WriteString("#ifdef __cplusplus\nextern \"C\" {\n#endif\n\n")
+	fmt.Fprintf(&h, "/* number of features of a row */\n#define %s_NUM_FEATURE %d\n\n", macro, m.NumFeature)
+	if t == model.TransformSoftmax {
+		fmt.Fprintf(&h, "/* number of classes of the model */\n#define %s_NUM_CLASS %d\n\n", macro, m.NumGroup)
+		fmt.Fprintf(&h, "/* %s predict the class of a row, NaN features are missing */\n", name)
+		fmt.Fprintf(&h, "float %s(const float *features);\n\n", name)
+		fmt.Fprintf(&h, "/* %s_proba write the probability of every class for a row to probs */\n", name)
+		fmt.Fprintf(&h, "void %s_proba(const float *features, float *probs);\n\n", name)
+	} else {
+		fmt.Fprintf(&h, "/* %s predict a row, NaN features are missing */\n", name)
+		fmt.Fprintf(&h, "float %s(const float *features);\n\n", name)
+	}
+	h.WriteString("#ifdef __cplusplus\n}\n#endif\n\n")
+	fmt.Fprintf(&h, "#endif /* %s_ */\n", guard)
+
+	var c bytes.Buffer
+	c.WriteString(generated)
+	fmt.Fprintf(&c, "#include <math.h>\n\n#include \"%s\"\n", opts.Header)
+	for i, tree := range m.Trees {
+		fmt.Fprintf(&c, "\nstatic float %s_tree%d(const float *f) {\n", name, i)
+		if tree.Nodes[0].IsLeaf() {
+			c.WriteString("    (void)f;\n")
+		}
+		cNode(&c, tree, 0, 1)
+		c.WriteString("}\n")
+	}
+	c.WriteString("\n")
+
+	switch t {
+	case model.TransformSoftmax:
+		fmt.Fprintf(&c, "static void %s_margins(const float *f, float *margins) {\n", name)
+		fmt.Fprintf(&c, "    int i;\n    for (i = 0; i < %s_NUM_CLASS; i++) {\n", macro)
+		fmt.Fprintf(&c, "        margins[i] = %sf;\n    }\n", formatFloat(m.BaseMargin))
+		for i := range m.Trees {
+			fmt.Fprintf(&c, "    margins[%d] += %s_tree%d(f);\n", m.TreeGroups[i], name, i)
+		}
+		c.WriteString("}\n\n")
+
+		fmt.Fprintf(&c, "float %s(const float *features) {\n", name)
+		fmt.Fprintf(&c, "    float margins[%s_NUM_CLASS];\n    int i, best = 0;\n", macro)
+		fmt.Fprintf(&c, "    %s_margins(features, margins);\n", name)
+		fmt.Fprintf(&c, "    for (i = 1; i < %s_NUM_CLASS; i++) {\n", macro)
+		c.WriteString("        if (margins[i] > margins[best]) {\n            best = i;\n        }\n    }\n")
+		c.WriteString("    return (float)best;\n}\n\n")
+
+		fmt.Fprintf(&c, "void %s_proba(const float *features, float *probs) {\n", name)
+		c.WriteString("    double sum = 0;\n    float max;\n    int i;\n")
+		fmt.Fprintf(&c, "    %s_margins(features, probs);\n", name)
+		c.WriteString("    max = probs[0];\n")
+		fmt.Fprintf(&c, "    for (i = 1; i < %s_NUM_CLASS; i++) {\n", macro)
+		c.WriteString("        if (probs[i] > max) {\n            max = probs[i];\n        }\n    }\n")
+		fmt.Fprintf(&c, "    for (i = 0; i < %s_NUM_CLASS; i++) {\n", macro)
+		c.WriteString("        probs[i] = (float)exp((double)(probs[i] - max));\n        sum += probs[i];\n    }\n")
+		fmt.Fprintf(&c, "    for (i = 0; i < %s_NUM_CLASS; i++) {\n", macro)
+		c.WriteString("        probs[i] = (float)(probs[i] / sum);\n    }\n}\n")
+	default:
+		fmt.Fprintf(&c, "float %s(const float *features) {\n", name)
+		fmt.Fprintf(&c, "    float margin = %sf;\n", formatFloat(m.BaseMargin))
+		for i := range m.Trees {
+			fmt.Fprintf(&c, "    margin += %s_tree%d(features);\n", name, i)
+		}
+		switch t {
+		case model.TransformSigmoid:
+			c.WriteString("    return (float)(1.0 / (1.0 + exp(-(double)margin)));\n")
+		case model.TransformExp:
+			c.WriteString("    return (float)exp((double)margin);\n")
+		case model.TransformHinge:
+			c.WriteString("    return margin > 0 ? 1.0f : 0.0f;\n")
+		default:
+			c.WriteString("    return margin;\n")
+		}
+		c.WriteString("}\n")
+	}
+
+	if _, err := header.Write(h.Bytes()); err != nil {
+		return err
+	}
+	_, err = source.Write(c.Bytes())
+	return err
+}
+
+// cNode write the statements of node id at the indentation depth, which return the
+// leaf value of the row
+func cNode(b *bytes.Buffer, tree *model.Tree, id, depth int) {
+	indent := strings.Repeat("    ", depth)
+	n := &tree.Nodes[id]
+	if n.IsLeaf() {
+		fmt.Fprintf(b, "%sreturn %sf;\n", indent, formatFloat(n.Leaf))
+		return
+	}
+	// a NaN compares false, so a missing value goes to No unless tested for
+	condition := fmt.Sprintf("f[%d] < %sf", n.Feature, formatFloat(n.Threshold))
+	if n.Missing == n.Yes {
+		condition += fmt.Sprintf(" || isnan(f[%d])", n.Feature)
+	}
+	fmt.Fprintf(b, "%sif (%s) {\n", indent, condition)
+	cNode(b, tree, n.Yes, depth+1)
+	fmt.Fprintf(b, "%s}\n", indent)
+	cNode(b, tree, n.No, depth)
+}
+
+func isIdentifier(name string) bool {
+	for i, r := range name {
+		if r >= unicode.MaxASCII || !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,168 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testmodel"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+const cMain = `#include <stdio.h>
+#include <stdlib.h>
+
+#include "model.h"
+
+int main(void) {
+    float row[PREDICT_NUM_FEATURE];
+    char value[64];
+    int i;
+    for (;;) {
+        for (i = 0; i < PREDICT_NUM_FEATURE; i++) {
+            if (scanf("%63s", value) != 1) {
+                return 0;
+            }
+            row[i] = strtof(value, NULL);
+        }
+        printf("%.9g", predict(row));
+#ifdef PREDICT_NUM_CLASS
+        {
+            float probs[PREDICT_NUM_CLASS];
+            predict_proba(row, probs);
+            for (i = 0; i < PREDICT_NUM_CLASS; i++) {
+                printf(" %.9g", probs[i]);
+            }
+        }
+#endif
+        printf("\n");
+    }
+}
+`
+
+// runC compile the generated source with a main program printing the predictions of
+// rows, and return them
+func runC(t *testing.T, m *model.TreeModel, rows model.Matrix) ([]float32, [][]float32) {
+	cc, err := exec.LookPath("cc")
+	if err != nil {
+		t.Skip("C compiler not found")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var header, source bytes.Buffer
+	if err := GenerateC(&header, &source, m, COptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string][]byte{"model.h": header.Bytes(), "model.c": source.Bytes(), "main.c": []byte(cMain)} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bin := filepath.Join(dir, "predict")
+	out, err := exec.Command(cc, "-std=c99", "-pedantic", "-Wall", "-Wextra", "-Werror",
+		"-o", bin, filepath.Join(dir, "main.c"), filepath.Join(dir, "model.c"), "-lm").CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s\n%s", err, out, source.String())
+	}
+
+	var input bytes.Buffer
+	for _, row := range rows {
+		for j := 0; j < m.NumFeature; j++ {
+			v := float32(math.NaN())
+			if j < len(row) {
+				v = row[j]
+			}
+			fmt.Fprintf(&input, "%v ", v)
+		}
+		input.WriteString("\n")
+	}
+	cmd := exec.Command(bin)
+	cmd.Stdin = &input
+	out, err = cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var preds []float32
+	var proba [][]float32
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		var values []float32
+		for _, field := range strings.Fields(line) {
+			v, err := strconv.ParseFloat(field, 32)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, float32(v))
+		}
+		preds = append(preds, values[0])
+		if len(values) > 1 {
+			proba = append(proba, values[1:])
+		}
+	}
+	if len(preds) != len(rows) {
+		t.Fatalf("got %d predictions for %d rows", len(preds), len(rows))
+	}
+	return preds, proba
+}
+
+func TestGenerateC(t *testing.T) {
+	for _, c := range []struct {
+		objective string
+		numGroup  int
+	}{
+		{"reg:linear", 1},
+		{"binary:logistic", 1},
+		{"count:poisson", 1},
+		{"binary:hinge", 1},
+		{"multi:softprob", 3},
+	} {
+		t.Run(c.objective, func(t *testing.T) {
+			m := testmodel.Model(t, c.objective, c.numGroup)
+			preds, proba := runC(t, m, testRows)
+			for i, row := range testRows {
+				want, err := m.Predict(row)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.numGroup > 1 {
+					for j := range want {
+						if math.Abs(float64(proba[i][j]-want[j])) > 1e-6 {
+							t.Errorf("row %v: generated %v, model %v", row, proba[i], want)
+						}
+					}
+					want = model.TransformArgmax.Apply(m.PredictMargin(row, 0))
+				}
+				if math.Abs(float64(preds[i]-want[0])) > 1e-6 {
+					t.Errorf("row %v: generated %v, model %v", row, preds[i], want[0])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCOptions(t *testing.T) {
+	m := testmodel.Model(t, "multi:softprob", 3)
+	var header, source bytes.Buffer
+	if err := GenerateC(&header, &source, m, COptions{FuncName: "churn", Header: "churn-model.h"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"#ifndef CHURN_MODEL_H_", "#define CHURN_NUM_CLASS 3", "void churn_proba(const float *features, float *probs);"} {
+		if !strings.Contains(header.String(), want) {
+			t.Errorf("generated header has no %q", want)
+		}
+	}
+	if !strings.Contains(source.String(), "#include \"churn-model.h\"") {
+		t.Error("generated source does not include the header")
+	}
+	if err := GenerateC(&header, &source, m, COptions{FuncName: "2predict"}); err == nil {
+		t.Error("expected an error for an invalid function name")
+	}
+}
@@ -0,0 +1,41 @@
+// Package codegen compiles tree models into standalone Go or C source code, nested
+// if/else statements for every tree followed by the output transform of the objective.
+// The generated code does not depend on this module.
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// objectiveTransform get the output transform of the objective, checking that the
+// model has the single output of a regression or the groups of a multiclass model
+func objectiveTransform(m *model.TreeModel) (model.OutputTransform, error) {
+	if err := m.Validate(); err != nil {
+		return 0, err
+	}
+	t, err := model.ObjectiveTransform(m.Objective)
+	if err != nil {
+		return 0, err
+	}
+	if t == model.TransformSoftmax || t == model.TransformArgmax {
+		return model.TransformSoftmax, nil
+	}
+	if m.NumGroup != 1 {
+		return 0, fmt.Errorf("cannot generate code for %d outputs of a %s model", m.NumGroup, m.Objective)
+	}
+	return t, nil
+}
+
+// formatFloat format v so that it parses back to the same float32
+func formatFloat(v float32) string {
+	s := strconv.FormatFloat(float64(v), 'g', -1, 32)
+	for _, c := range s {
+		if c == '.' || c == 'e' || c == 'n' || c == 'I' {
+			return s
+		}
+	}
+	return s + ".0"
+}
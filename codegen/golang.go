@@ -0,0 +1,145 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// GoOptions configure the generated Go source
+type GoOptions struct {
+	// Package is the package name of the source, "model" by default
+	Package string
+	// FuncName is the name of the prediction function, "Predict" by default. Unexported
+	// helpers are named after it, so several models can be generated in one package.
+	FuncName string
+	// Command is mentioned in the generated code header, e.g. the go:generate command
+	Command string
+}
+
+// GenerateGo write Go source predicting with m. It has a function
+//
+//	func Predict(features []float32) float32
+//
+// giving the prediction of Booster.Predict for a row, NaN or absent features are
+// missing. For multiclass models it gives the predicted class, and a PredictProba
+// function gives the probability of every class.
+func GenerateGo(w io.Writer, m *model.TreeModel, opts GoOptions) error {
+	t, err := objectiveTransform(m)
+	if err != nil {
+		return err
+	}
+	if opts.Package == "" {
+		opts.Package = "model"
+	}
+	if opts.FuncName == "" {
+		opts.FuncName = "Predict"
+	}
+	if !isExported(opts.FuncName) {
+		return fmt.Errorf("function name %q is not an exported identifier", opts.FuncName)
+	}
+	prefix := strings.ToLower(opts.FuncName[:1]) + opts.FuncName[1:]
+	command := "xgb-codegen"
+	if opts.Command != "" {
+		command = opts.Command
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by %s. DO NOT EDIT.\n\n", command)
+	fmt.Fprintf(&b, "package %s\n\n", opts.Package)
+	if t != model.TransformNone && t != model.TransformHinge {
+		b.WriteString("import \"math\"\n\n")
+	}
+	fmt.Fprintf(&b, "// %sNumFeature is the number of features of the model\n", opts.FuncName)
+	fmt.Fprintf(&b, "const %sNumFeature = %d\n\n", opts.FuncName, m.NumFeature)
+
+	switch t {
+	case model.TransformSoftmax:
+		fmt.Fprintf(&b, "// %s predict the class of a row, NaN or absent features are missing\n", opts.FuncName)
+		fmt.Fprintf(&b, "func %s(features []float32) float32 {\n", opts.FuncName)
+		fmt.Fprintf(&b, "margins := %sMargins(features)\n", prefix)
+		b.WriteString("best := 0\nfor i, v := range margins {\nif v > margins[best] {\nbest = i\n}\n}\nreturn float32(best)\n}\n\n")
+		fmt.Fprintf(&b, "// %sProba predict the probability of every class for a row\n", opts.FuncName)
+		fmt.Fprintf(&b, "func %sProba(features []float32) []float32 {\n", opts.FuncName)
+		fmt.Fprintf(&b, "margins := %sMargins(features)\n", prefix)
+		b.WriteString("max := margins[0]\nfor _, v := range margins {\nif v > max {\nmax = v\n}\n}\n")
+		b.WriteString("var sum float64\nprobs := make([]float32, len(margins))\nfor i, v := range margins {\ne := math.Exp(float64(v - max))\nprobs[i] = float32(e)\nsum += e\n}\n")
+		b.WriteString("for i := range probs {\nprobs[i] = float32(float64(probs[i]) / sum)\n}\nreturn probs\n}\n\n")
+
+		fmt.Fprintf(&b, "func %sMargins(f []float32) [%d]float32 {\n", prefix, m.NumGroup)
+		fmt.Fprintf(&b, "var margins [%d]float32\n", m.NumGroup)
+		for g := 0; g < m.NumGroup; g++ {
+			fmt.Fprintf(&b, "margins[%d] = %s\n", g, formatFloat(m.BaseMargin))
+		}
+		for i := range m.Trees {
+			fmt.Fprintf(&b, "margins[%d] += %sTree%d(f)\n", m.TreeGroups[i], prefix, i)
+		}
+		b.WriteString("return margins\n}\n\n")
+	default:
+		fmt.Fprintf(&b, "// %s predict a row, NaN or absent features are missing\n", opts.FuncName)
+		fmt.Fprintf(&b, "func %s(f []float32) float32 {\n", opts.FuncName)
+		fmt.Fprintf(&b, "margin := float32(%s)\n", formatFloat(m.BaseMargin))
+		for i := range m.Trees {
+			fmt.Fprintf(&b, "margin += %sTree%d(f)\n", prefix, i)
+		}
+		switch t {
+		case model.TransformSigmoid:
+			b.WriteString("return float32(1 / (1 + math.Exp(-float64(margin))))\n")
+		case model.TransformExp:
+			b.WriteString("return float32(math.Exp(float64(margin)))\n")
+		case model.TransformHinge:
+			b.WriteString("if margin > 0 {\nreturn 1\n}\nreturn 0\n")
+		default:
+			b.WriteString("return margin\n")
+		}
+		b.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(&b, "func %sFeature(f []float32, i int) float32 {\n", prefix)
+	b.WriteString("if i < len(f) {\nreturn f[i]\n}\n")
+	b.WriteString("var zero float32\nreturn zero / zero\n}\n")
+	for i, tree := range m.Trees {
+		fmt.Fprintf(&b, "\nfunc %sTree%d(f []float32) float32 {\n", prefix, i)
+		goNode(&b, tree, 0, prefix)
+		b.WriteString("}\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return fmt.Errorf("format generated source: %v", err)
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+// goNode write the statements of node id, which return the leaf value of the row
+func goNode(b *bytes.Buffer, tree *model.Tree, id int, prefix string) {
+	n := &tree.Nodes[id]
+	if n.IsLeaf() {
+		fmt.Fprintf(b, "return %s\n", formatFloat(n.Leaf))
+		return
+	}
+	// a NaN compares false, so a missing value goes to No unless tested for
+	condition := fmt.Sprintf("x := %sFeature(f, %d); x < %s", prefix, n.Feature, formatFloat(n.Threshold))
+	if n.Missing == n.Yes {
+		condition += " || x != x"
+	}
+	fmt.Fprintf(b, "if %s {\n", condition)
+	goNode(b, tree, n.Yes, prefix)
+	b.WriteString("}\n")
+	goNode(b, tree, n.No, prefix)
+}
+
+func isExported(name string) bool {
+	for i, r := range name {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != "" && unicode.IsUpper([]rune(name)[0])
+}
@@ -0,0 +1,182 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testmodel"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// testRows are the shared rows and one shorter than the features of the model, absent
+// features being missing
+var testRows = append(testmodel.Rows[:len(testmodel.Rows):len(testmodel.Rows)], []float32{2.5, -1})
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "xgboost-codegen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// runGo compile the generated package with a main program printing the predictions of
+// rows as JSON, NaN encoded as null, and return them
+func runGo(t *testing.T, m *model.TreeModel, rows model.Matrix) ([]float32, [][]float32) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	var src bytes.Buffer
+	if err := GenerateGo(&src, m, GoOptions{Package: "scoring", FuncName: "PredictTest"}); err != nil {
+		t.Fatal(err)
+	}
+	proba := ""
+	if m.NumGroup > 1 {
+		proba = "proba = append(proba, scoring.PredictTestProba(row))"
+	}
+	files := map[string]string{
+		"go.mod":             "module gen\n\ngo 1.13\n",
+		"scoring/scoring.go": src.String(),
+		"main.go": `package main
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+
+	"gen/scoring"
+)
+
+func main() {
+	var input [][]*float32
+	if err := json.NewDecoder(os.Stdin).Decode(&input); err != nil {
+		panic(err)
+	}
+	var preds []float32
+	var proba [][]float32
+	for _, values := range input {
+		row := make([]float32, len(values))
+		for i, v := range values {
+			row[i] = float32(math.NaN())
+			if v != nil {
+				row[i] = *v
+			}
+		}
+		preds = append(preds, scoring.PredictTest(row))
+		` + proba + `
+	}
+	json.NewEncoder(os.Stdout).Encode(map[string]interface{}{"preds": preds, "proba": proba})
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	input := make([][]*float32, len(rows))
+	for i, row := range rows {
+		for j := range row {
+			if v := row[j]; v == v {
+				input[i] = append(input[i], &v)
+			} else {
+				input[i] = append(input[i], nil)
+			}
+		}
+	}
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GO111MODULE=on")
+	cmd.Stdin = bytes.NewReader(encoded)
+	out, err := cmd.Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			t.Fatalf("%v: %s\n%s", err, exit.Stderr, src.String())
+		}
+		t.Fatal(err)
+	}
+	var result struct {
+		Preds []float32
+		Proba [][]float32
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result.Preds, result.Proba
+}
+
+func TestGenerateGo(t *testing.T) {
+	for _, c := range []struct {
+		objective string
+		numGroup  int
+	}{
+		{"reg:linear", 1},
+		{"binary:logistic", 1},
+		{"count:poisson", 1},
+		{"binary:hinge", 1},
+		{"multi:softprob", 3},
+	} {
+		t.Run(c.objective, func(t *testing.T) {
+			m := testmodel.Model(t, c.objective, c.numGroup)
+			preds, proba := runGo(t, m, testRows)
+			for i, row := range testRows {
+				want, err := m.Predict(row)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if c.numGroup > 1 {
+					for j := range want {
+						if math.Abs(float64(proba[i][j]-want[j])) > 1e-6 {
+							t.Errorf("row %v: generated %v, model %v", row, proba[i], want)
+						}
+					}
+					want = model.TransformArgmax.Apply(m.PredictMargin(row, 0))
+				}
+				if math.Abs(float64(preds[i]-want[0])) > 1e-6 {
+					t.Errorf("row %v: generated %v, model %v", row, preds[i], want[0])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateGoOptions(t *testing.T) {
+	m := testmodel.Model(t, "reg:linear", 1)
+	var src bytes.Buffer
+	if err := GenerateGo(&src, m, GoOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package model\n", "func Predict(f []float32) float32", "func predictTree2(", "const PredictNumFeature = 3"} {
+		if !strings.Contains(src.String(), want) {
+			t.Errorf("generated source has no %q", want)
+		}
+	}
+	if err := GenerateGo(&src, m, GoOptions{FuncName: "predict"}); err == nil {
+		t.Error("expected an error for an unexported function name")
+	}
+	if err := GenerateGo(&src, testmodel.Model(t, "reg:linear", 2), GoOptions{}); err == nil {
+		t.Error("expected an error for a regression with two groups")
+	}
+	if err := GenerateGo(&src, testmodel.Model(t, "reg:unknown", 1), GoOptions{}); err == nil {
+		t.Error("expected an error for an unknown objective")
+	}
+}
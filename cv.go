@@ -0,0 +1,363 @@
+package xgboost
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CVOptions tune how CV builds its folds and when it stops
+type CVOptions struct {
+	// Stratified keep the label distribution of every fold close to the whole data, for classification
+	Stratified bool
+	// Groups holds the query group sizes of data, as given to SetGroup. When set, whole
+	// groups are assigned to folds and the fold matrices get their groups set.
+	Groups []uint32
+	// Seed seeds the shuffling of rows (or groups) before they are dealt into folds
+	Seed int64
+	// EarlyStoppingRounds stops CV when the mean of the watched metric has not improved
+	// for this many rounds; 0 disables early stopping
+	EarlyStoppingRounds int
+	// Metric is the "test-<metric>" entry watched for early stopping; defaults to the last test metric
+	Metric string
+	// Maximize tells whether a larger metric is better; guessed from the metric name when nil
+	Maximize *bool
+}
+
+// CVResult holds per-round mean and standard deviation over all folds of every metric
+type CVResult struct {
+	// Metrics lists the "<data>-<metric>" names, e.g. "train-rmse", "test-rmse"
+	Metrics []string
+	Mean    map[string][]float64
+	Std     map[string][]float64
+	// BestIteration is the round with the best mean of the watched metric
+	BestIteration int
+	BestScore     float64
+	// Watched is the "test-<metric>" name of the watched metric, Maximize whether a
+	// larger value of it is better
+	Watched  string
+	Maximize bool
+}
+
+// Rounds get the number of rounds in the result
+func (r *CVResult) Rounds() int {
+	if len(r.Metrics) == 0 {
+		return 0
+	}
+	return len(r.Mean[r.Metrics[0]])
+}
+
+type cvFold struct {
+	booster *Booster
+	dtrain  *DMatrix
+	dtest   *DMatrix
+}
+
+func (fold *cvFold) free() {
+	if fold.booster != nil {
+		fold.booster.Free()
+	}
+	if fold.dtrain != nil {
+		fold.dtrain.Free()
+	}
+	if fold.dtest != nil {
+		fold.dtest.Free()
+	}
+}
+
+// CV run nfold cross validation of rounds boosting rounds over data. The fold boosters
+// are trained concurrently and every round is evaluated on the "train" and "test" split
+// of each fold.
+func CV(params Params, data *DMatrix, nfold int, rounds int, opts CVOptions) (*CVResult, error) {
+	if nfold < 2 {
+		return nil, fmt.Errorf("nfold must be at least 2, got %d", nfold)
+	}
+	numRow, err := data.NumRow()
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []float32
+	if opts.Stratified {
+		if labels, err = data.GetLabels(); err != nil {
+			return nil, err
+		}
+	}
+	testIdx, err := makeFolds(int(numRow), nfold, labels, opts.Groups, opts.Seed)
+	if err != nil {
+		return nil, err
+	}
+
+	folds := make([]*cvFold, nfold)
+	defer func() {
+		for _, fold := range folds {
+			if fold != nil {
+				fold.free()
+			}
+		}
+	}()
+	for k := range folds {
+		if folds[k], err = newCVFold(params, data, testIdx, k, opts.Groups); err != nil {
+			return nil, fmt.Errorf("fold %d: %v", k, err)
+		}
+	}
+
+	result := &CVResult{
+		Mean:          map[string][]float64{},
+		Std:           map[string][]float64{},
+		BestIteration: -1,
+	}
+	watch := opts.Metric
+	for iter := 0; iter < rounds; iter++ {
+		evals, err := cvRound(folds, iter)
+		if err != nil {
+			return nil, err
+		}
+		if iter == 0 {
+			for _, e := range evals[0] {
+				result.Metrics = append(result.Metrics, e.Name())
+				if e.Data == "test" && opts.Metric == "" {
+					watch = e.Name()
+				}
+			}
+			if len(result.Metrics) == 0 {
+				return nil, errors.New("no evaluation metrics reported")
+			}
+			if !contains(result.Metrics, watch) {
+				return nil, fmt.Errorf("metric %q not found in %v", watch, result.Metrics)
+			}
+			result.Watched = watch
+			if opts.Maximize != nil {
+				result.Maximize = *opts.Maximize
+			} else {
+				result.Maximize = MetricMaximize(strings.TrimPrefix(watch, "test-"))
+			}
+		}
+		if err := result.add(evals); err != nil {
+			return nil, fmt.Errorf("round %d: %v", iter, err)
+		}
+
+		score := result.Mean[watch][iter]
+		if result.BestIteration < 0 || (result.Maximize && score > result.BestScore) || (!result.Maximize && score < result.BestScore) {
+			result.BestIteration = iter
+			result.BestScore = score
+		}
+		if opts.EarlyStoppingRounds > 0 && iter-result.BestIteration >= opts.EarlyStoppingRounds {
+			result.truncate(result.BestIteration + 1)
+			break
+		}
+	}
+	return result, nil
+}
+
+func newCVFold(params Params, data *DMatrix, testIdx [][]int, k int, groups []uint32) (*cvFold, error) {
+	var trainIdx []int
+	for j, idx := range testIdx {
+		if j != k {
+			trainIdx = append(trainIdx, idx...)
+		}
+	}
+	if groups != nil {
+		// keep the groups contiguous and in their original order
+		sort.Ints(trainIdx)
+	}
+
+	fold := &cvFold{}
+	var err error
+	if fold.dtrain, err = DMatrixSliceDMatrix(data, trainIdx); err != nil {
+		fold.free()
+		return nil, err
+	}
+	if fold.dtest, err = DMatrixSliceDMatrix(data, testIdx[k]); err != nil {
+		fold.free()
+		return nil, err
+	}
+	if groups != nil {
+		if err = fold.dtrain.SetGroup(groupSizes(groups, trainIdx)...); err != nil {
+			fold.free()
+			return nil, err
+		}
+		if err = fold.dtest.SetGroup(groupSizes(groups, testIdx[k])...); err != nil {
+			fold.free()
+			return nil, err
+		}
+	}
+	if fold.booster, err = BoosterCreate([]*DMatrix{fold.dtrain, fold.dtest}); err != nil {
+		fold.free()
+		return nil, err
+	}
+	if err = fold.booster.SetParams(params); err != nil {
+		fold.free()
+		return nil, err
+	}
+	return fold, nil
+}
+
+// cvRound boost and evaluate every fold concurrently
+func cvRound(folds []*cvFold, iter int) ([][]EvalResult, error) {
+	evals := make([][]EvalResult, len(folds))
+	errs := make([]error, len(folds))
+	var wg sync.WaitGroup
+	for k, fold := range folds {
+		wg.Add(1)
+		go func(k int, fold *cvFold) {
+			defer wg.Done()
+			if err := fold.booster.UpdateOneIter(iter, fold.dtrain); err != nil {
+				errs[k] = err
+				return
+			}
+			res, err := fold.booster.EvalOneIter(iter, []*DMatrix{fold.dtrain, fold.dtest}, []string{"train", "test"})
+			if err != nil {
+				errs[k] = err
+				return
+			}
+			evals[k], errs[k] = ParseEvalResult(res, []string{"train", "test"})
+		}(k, fold)
+	}
+	wg.Wait()
+	for k, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("fold %d round %d: %v", k, iter, err)
+		}
+	}
+	return evals, nil
+}
+
+func (r *CVResult) add(evals [][]EvalResult) error {
+	for i, name := range r.Metrics {
+		values := make([]float64, len(evals))
+		for k, fold := range evals {
+			if i >= len(fold) || fold[i].Name() != name {
+				return fmt.Errorf("fold %d does not report %s", k, name)
+			}
+			values[k] = fold[i].Value
+		}
+		mean, std := meanStd(values)
+		r.Mean[name] = append(r.Mean[name], mean)
+		r.Std[name] = append(r.Std[name], std)
+	}
+	return nil
+}
+
+func (r *CVResult) truncate(rounds int) {
+	for _, name := range r.Metrics {
+		r.Mean[name] = r.Mean[name][:rounds]
+		r.Std[name] = r.Std[name][:rounds]
+	}
+}
+
+// makeFolds deal the row indices 0..numRow-1 into nfold test folds. With labels the
+// rows of every label are dealt separately (stratified), with groups whole groups are dealt.
+func makeFolds(numRow int, nfold int, labels []float32, groups []uint32, seed int64) ([][]int, error) {
+	rnd := rand.New(rand.NewSource(seed))
+	folds := make([][]int, nfold)
+
+	switch {
+	case groups != nil:
+		var total int
+		starts := make([]int, len(groups))
+		for i, size := range groups {
+			starts[i] = total
+			total += int(size)
+		}
+		if total != numRow {
+			return nil, fmt.Errorf("groups cover %d rows, matrix has %d", total, numRow)
+		}
+		if len(groups) < nfold {
+			return nil, fmt.Errorf("%d groups cannot be split into %d folds", len(groups), nfold)
+		}
+		order := rnd.Perm(len(groups))
+		for i, g := range order {
+			k := i % nfold
+			for j := 0; j < int(groups[g]); j++ {
+				folds[k] = append(folds[k], starts[g]+j)
+			}
+		}
+		for _, fold := range folds {
+			sort.Ints(fold)
+		}
+	case labels != nil:
+		if len(labels) != numRow {
+			return nil, fmt.Errorf("got %d labels for %d rows", len(labels), numRow)
+		}
+		byLabel := map[float32][]int{}
+		var classes []float32
+		for i, label := range labels {
+			if _, ok := byLabel[label]; !ok {
+				classes = append(classes, label)
+			}
+			byLabel[label] = append(byLabel[label], i)
+		}
+		sort.Slice(classes, func(i, j int) bool { return classes[i] < classes[j] })
+		// continue dealing where the previous class stopped so fold sizes stay even
+		var next int
+		for _, class := range classes {
+			rows := byLabel[class]
+			rnd.Shuffle(len(rows), func(i, j int) { rows[i], rows[j] = rows[j], rows[i] })
+			for _, row := range rows {
+				folds[next%nfold] = append(folds[next%nfold], row)
+				next++
+			}
+		}
+	default:
+		for i, row := range rnd.Perm(numRow) {
+			folds[i%nfold] = append(folds[i%nfold], row)
+		}
+	}
+
+	for k, fold := range folds {
+		if len(fold) == 0 {
+			return nil, fmt.Errorf("fold %d is empty", k)
+		}
+	}
+	return folds, nil
+}
+
+// groupSizes get the sizes of the groups covered by the sorted row indices idx
+func groupSizes(groups []uint32, idx []int) []uint32 {
+	var sizes []uint32
+	var start int
+	pos := 0
+	for _, size := range groups {
+		end := start + int(size)
+		var n uint32
+		for pos < len(idx) && idx[pos] < end {
+			n++
+			pos++
+		}
+		if n > 0 {
+			sizes = append(sizes, n)
+		}
+		start = end
+	}
+	return sizes
+}
+
+func meanStd(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return math.NaN(), math.NaN()
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)))
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
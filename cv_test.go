@@ -0,0 +1,89 @@
+package xgboost
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestMakeFolds(t *testing.T) {
+	labels := []float32{0, 0, 0, 0, 0, 0, 1, 1, 1, 1, 1, 1}
+	folds, err := makeFolds(len(labels), 3, labels, nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []int
+	for k, fold := range folds {
+		positives := 0
+		for _, row := range fold {
+			if labels[row] == 1 {
+				positives++
+			}
+		}
+		if len(fold) != 4 || positives != 2 {
+			t.Errorf("fold %d is not stratified: %v", k, fold)
+		}
+		all = append(all, fold...)
+	}
+	sort.Ints(all)
+	for i, row := range all {
+		if row != i {
+			t.Fatalf("folds do not cover every row once: %v", all)
+		}
+	}
+
+	groups := []uint32{2, 3, 1, 2}
+	folds, err = makeFolds(8, 2, nil, groups, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, fold := range folds {
+		for _, size := range groupSizes(groups, fold) {
+			if size != 1 && size != 2 && size != 3 {
+				t.Errorf("fold %d splits a group: %v", k, fold)
+			}
+		}
+		var total uint32
+		for _, size := range groupSizes(groups, fold) {
+			total += size
+		}
+		if int(total) != len(fold) {
+			t.Errorf("fold %d group sizes do not add up: %v", k, fold)
+		}
+	}
+
+	if _, err := makeFolds(8, 5, nil, groups, 1); err == nil {
+		t.Error("expected error for more folds than groups")
+	}
+}
+
+func TestCV(t *testing.T) {
+	dtrain := trainTestMatrix(t)
+	defer dtrain.Free()
+
+	params := Params{
+		"objective":   "reg:linear",
+		"max_depth":   "3",
+		"eta":         "0.3",
+		"eval_metric": "rmse",
+		"silent":      "1",
+	}
+	result, err := CV(params, dtrain, 4, 30, CVOptions{Seed: 7, EarlyStoppingRounds: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Metrics) != 2 || result.Metrics[0] != "train-rmse" || result.Metrics[1] != "test-rmse" {
+		t.Fatalf("unexpected metrics %v", result.Metrics)
+	}
+	if result.Rounds() == 0 || result.Rounds() != result.BestIteration+1 {
+		t.Errorf("expected result truncated to best iteration %d, got %d rounds", result.BestIteration, result.Rounds())
+	}
+	train := result.Mean["train-rmse"]
+	if train[len(train)-1] >= train[0] {
+		t.Errorf("train rmse did not decrease: %v", train)
+	}
+	for _, std := range result.Std["test-rmse"] {
+		if std < 0 {
+			t.Errorf("negative std %v", std)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package distributed runs distributed training on a single machine: it splits a
+// dataset into shards, starts one rabit worker process per shard against an
+// in-process tracker, and collects the trained model.
+//
+// The worker processes are the launching program itself unless a command is given,
+// so a program using Job.Run starts with
+//
+//	if distributed.IsWorker() {
+//		if err := distributed.RunWorker(); err != nil {
+//			log.Fatalln(err)
+//		}
+//		return
+//	}
+package distributed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/tracker"
+)
+
+// Job describes a local distributed training run
+type Job struct {
+	// Data is the LibSVM file to train on, split into one shard per worker
+	Data    string
+	Workers int
+	Params  xgboost.Params
+	Rounds  int
+	// Dir holds the shards and the model; a temporary directory is used when empty
+	Dir string
+	// Command starts a worker; defaults to the current executable
+	Command []string
+	// Restarts is how many times a failed worker is restarted; it rejoins the job from
+	// the latest rabit checkpoint
+	Restarts int
+	// Env is added to the environment of every worker
+	Env []string
+	// Stdout and Stderr receive the output of the workers and the tracker; discarded when nil
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Run train and return the raw model of the worker with rank 0
+func (job *Job) Run() ([]byte, error) {
+	if job.Workers < 1 {
+		return nil, fmt.Errorf("need at least one worker, got %d", job.Workers)
+	}
+	if job.Rounds < 1 {
+		return nil, fmt.Errorf("need at least one round, got %d", job.Rounds)
+	}
+
+	dir := job.Dir
+	if dir == "" {
+		tmp, err := ioutil.TempDir("", "xgboost-dist")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmp)
+		dir = tmp
+	}
+	shards, err := SplitLibSVM(job.Data, job.Workers, dir)
+	if err != nil {
+		return nil, err
+	}
+	params, err := json.Marshal(job.Params)
+	if err != nil {
+		return nil, err
+	}
+	modelPath := filepath.Join(dir, "model.bin")
+	os.Remove(modelPath)
+
+	t, err := tracker.New("127.0.0.1:0", job.Workers)
+	if err != nil {
+		return nil, err
+	}
+	// workers and the tracker write concurrently, possibly to the same writer
+	var outMu sync.Mutex
+	stdout, stderr := lockWriter(job.Stdout, &outMu), lockWriter(job.Stderr, &outMu)
+	t.Print = func(msg string) {
+		if stderr != nil {
+			io.WriteString(stderr, msg)
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Run()
+	}()
+
+	command := job.Command
+	if len(command) == 0 {
+		exe, err := os.Executable()
+		if err != nil {
+			t.Close()
+			return nil, err
+		}
+		command = []string{exe}
+	}
+
+	baseEnv := append(os.Environ(), t.WorkerEnv()...)
+	baseEnv = append(baseEnv,
+		"DMLC_ROLE=worker",
+		envWorker+"=1",
+		envParams+"="+string(params),
+		envRounds+"="+strconv.Itoa(job.Rounds),
+		envModel+"="+modelPath,
+	)
+	s := &supervisor{
+		job:     job,
+		command: command,
+		stdout:  stdout,
+		stderr:  stderr,
+		running: map[int]*exec.Cmd{},
+	}
+	errs := make([]error, job.Workers)
+	var wg sync.WaitGroup
+	for i := range errs {
+		env := append(append([]string{}, baseEnv...), "DMLC_TASK_ID="+strconv.Itoa(i), envShard+"="+shards[i])
+		wg.Add(1)
+		go func(task int, env []string) {
+			defer wg.Done()
+			if errs[task] = s.run(task, env); errs[task] != nil {
+				// the job cannot finish without this worker, stop the others
+				s.abort()
+				t.Close()
+			}
+		}(i, env)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			<-done
+			return nil, err
+		}
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("tracker: %v", err)
+	}
+
+	raw, err := ioutil.ReadFile(modelPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("rank 0 did not write a model")
+		}
+		return nil, err
+	}
+	return raw, nil
+}
+
+// supervisor runs the worker processes of a job, restarting those that fail
+type supervisor struct {
+	job     *Job
+	command []string
+	stdout  io.Writer
+	stderr  io.Writer
+
+	mu      sync.Mutex
+	aborted bool
+	running map[int]*exec.Cmd
+}
+
+// run start the worker of task and restart it up to job.Restarts times when it fails
+func (s *supervisor) run(task int, env []string) error {
+	for attempt := 0; ; attempt++ {
+		cmd := exec.Command(s.command[0], s.command[1:]...)
+		cmd.Env = append(append([]string{}, env...), "DMLC_NUM_ATTEMPT="+strconv.Itoa(attempt))
+		cmd.Env = append(cmd.Env, s.job.Env...)
+		cmd.Stdout = s.stdout
+		cmd.Stderr = s.stderr
+
+		s.mu.Lock()
+		if s.aborted {
+			s.mu.Unlock()
+			return nil
+		}
+		err := cmd.Start()
+		if err == nil {
+			s.running[task] = cmd
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("worker %d: %v", task, err)
+		}
+
+		err = cmd.Wait()
+		s.mu.Lock()
+		delete(s.running, task)
+		aborted := s.aborted
+		s.mu.Unlock()
+		if err == nil || aborted {
+			return nil
+		}
+		if attempt >= s.job.Restarts {
+			return fmt.Errorf("worker %d: %v", task, err)
+		}
+		if s.stderr != nil {
+			fmt.Fprintf(s.stderr, "worker %d failed (%v), restarting\n", task, err)
+		}
+	}
+}
+
+// abort kill every running worker and start no more
+func (s *supervisor) abort() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aborted = true
+	for _, cmd := range s.running {
+		cmd.Process.Kill()
+	}
+}
+
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+// lockWriter guard w with mu, keeping nil as nil so that exec discards the output
+func lockWriter(w io.Writer, mu *sync.Mutex) io.Writer {
+	if w == nil {
+		return nil
+	}
+	return &lockedWriter{mu: mu, w: w}
+}
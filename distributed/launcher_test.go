@@ -0,0 +1,200 @@
+package distributed
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// TestMain run the worker side when the test binary is started by Job.Run
+func TestMain(m *testing.M) {
+	if IsWorker() {
+		// crash the first attempt of worker 1 after the given round
+		if round := os.Getenv("XGB_DIST_TEST_KILL_ROUND"); round != "" && os.Getenv("DMLC_TASK_ID") == "1" && os.Getenv("DMLC_NUM_ATTEMPT") == "0" {
+			kill, _ := strconv.Atoi(round)
+			roundHook = func(iter int) {
+				if iter == kill {
+					self, _ := os.FindProcess(os.Getpid())
+					self.Kill()
+				}
+			}
+		}
+		if err := RunWorker(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// writeTestData write a LibSVM file whose label is 1 when the first feature exceeds the second
+func writeTestData(t *testing.T, dir string, rows int) string {
+	var buf bytes.Buffer
+	for i := 0; i < rows; i++ {
+		a, b := float32(i%17), float32(i%11)
+		label := 0
+		if a > b {
+			label = 1
+		}
+		fmt.Fprintf(&buf, "%d 0:%v 1:%v\n", label, a, b)
+	}
+	path := filepath.Join(dir, "train.libsvm")
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestJobRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-dist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var out bytes.Buffer
+	job := &Job{
+		Data:    writeTestData(t, dir, 400),
+		Workers: 3,
+		Params: xgboost.Params{
+			"objective": "binary:logistic",
+			"max_depth": "3",
+			"eta":       "0.5",
+			"silent":    "1",
+		},
+		Rounds: 5,
+		Dir:    dir,
+		Stdout: &out,
+		Stderr: &out,
+	}
+	raw, err := job.Run()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	if err := booster.LoadModelFromBuffer(raw); err != nil {
+		t.Fatal(err)
+	}
+	trees, err := booster.DumpModel("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) != 5 {
+		t.Errorf("expected 5 trees, got %d", len(trees))
+	}
+
+	test, err := xgboost.DMatrixCreateFromMat(model.Matrix{{10, 1}, {1, 10}}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Free()
+	preds, err := booster.Predict(test, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preds[0] < 0.5 || preds[1] > 0.5 {
+		t.Errorf("distributed model predicts %v", preds)
+	}
+}
+
+// predict load raw and predict a few rows
+func predict(t *testing.T, raw []byte) []float32 {
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	if err := booster.LoadModelFromBuffer(raw); err != nil {
+		t.Fatal(err)
+	}
+	test, err := xgboost.DMatrixCreateFromMat(model.Matrix{{10, 1}, {1, 10}, {5, 5}, {16, 0}}, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Free()
+	preds, err := booster.Predict(test, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return preds
+}
+
+func TestJobRecovery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-dist-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := writeTestData(t, dir, 600)
+	params := xgboost.Params{
+		"objective": "binary:logistic",
+		"max_depth": "3",
+		"eta":       "0.3",
+		"silent":    "1",
+	}
+
+	var out bytes.Buffer
+	uninterrupted := &Job{Data: data, Workers: 3, Params: params, Rounds: 6, Dir: dir, Stdout: &out, Stderr: &out}
+	want, err := uninterrupted.Run()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+
+	out.Reset()
+	interrupted := &Job{
+		Data:     data,
+		Workers:  3,
+		Params:   params,
+		Rounds:   6,
+		Dir:      dir,
+		Restarts: 1,
+		Env:      []string{"XGB_DIST_TEST_KILL_ROUND=2"},
+		Stdout:   &out,
+		Stderr:   &out,
+	}
+	got, err := interrupted.Run()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out.String())
+	}
+	log := out.String()
+	if !strings.Contains(log, "worker 1 failed") || !strings.Contains(log, "resumed at round 3") {
+		t.Errorf("worker 1 was not killed and resumed:\n%s", log)
+	}
+
+	wantPreds, gotPreds := predict(t, want), predict(t, got)
+	for i := range wantPreds {
+		if diff := wantPreds[i] - gotPreds[i]; diff > 1e-5 || diff < -1e-5 {
+			t.Errorf("prediction %d: recovered run %v, uninterrupted run %v", i, gotPreds[i], wantPreds[i])
+		}
+	}
+
+	out.Reset()
+	noRestart := &Job{
+		Data:    data,
+		Workers: 3,
+		Params:  params,
+		Rounds:  6,
+		Dir:     dir,
+		Env:     []string{"XGB_DIST_TEST_KILL_ROUND=2"},
+		Stdout:  &out,
+		Stderr:  &out,
+	}
+	if _, err := noRestart.Run(); err == nil {
+		t.Error("expected error when a worker dies without restarts")
+	}
+}
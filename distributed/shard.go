@@ -0,0 +1,76 @@
+package distributed
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SplitLibSVM split the LibSVM text file data into n shard files in dir, dealing
+// rows round-robin, and return their paths. Blank lines and comments are dropped.
+func SplitLibSVM(data string, n int, dir string) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("need at least one shard, got %d", n)
+	}
+	in, err := os.Open(data)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	base := filepath.Base(data)
+	paths := make([]string, n)
+	files := make([]*os.File, n)
+	writers := make([]*bufio.Writer, n)
+	defer func() {
+		for _, f := range files {
+			if f != nil {
+				f.Close()
+			}
+		}
+	}()
+	for i := range files {
+		paths[i] = filepath.Join(dir, fmt.Sprintf("%s.part%d", base, i))
+		if files[i], err = os.Create(paths[i]); err != nil {
+			return nil, err
+		}
+		writers[i] = bufio.NewWriter(files[i])
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var rows int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		w := writers[rows%n]
+		if _, err := w.WriteString(line); err != nil {
+			return nil, err
+		}
+		if err := w.WriteByte('\n'); err != nil {
+			return nil, err
+		}
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rows < n {
+		return nil, fmt.Errorf("%s has %d rows, cannot split into %d shards", data, rows, n)
+	}
+
+	for i, w := range writers {
+		if err := w.Flush(); err != nil {
+			return nil, err
+		}
+		if err := files[i].Close(); err != nil {
+			return nil, err
+		}
+		files[i] = nil
+	}
+	return paths, nil
+}
@@ -0,0 +1,45 @@
+package distributed
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitLibSVM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-shard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := filepath.Join(dir, "train.libsvm")
+	content := "1 0:1 1:2\n# comment\n0 0:3\n\n1 1:4\n0 0:5 1:6\n1 0:7\n"
+	if err := ioutil.WriteFile(data, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shards, err := SplitLibSVM(data, 2, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"1 0:1 1:2\n1 1:4\n1 0:7\n", "0 0:3\n0 0:5 1:6\n"}
+	for i, shard := range shards {
+		got, err := ioutil.ReadFile(shard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want[i] {
+			t.Errorf("shard %d: got %q, expected %q", i, got, want[i])
+		}
+		if !strings.HasSuffix(shard, ".part"+string(rune('0'+i))) {
+			t.Errorf("unexpected shard name %s", shard)
+		}
+	}
+
+	if _, err := SplitLibSVM(data, 6, dir); err == nil {
+		t.Error("expected error for more shards than rows")
+	}
+}
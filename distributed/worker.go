@@ -0,0 +1,121 @@
+package distributed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/rabit"
+)
+
+// environment handed to worker processes on top of the rabit DMLC_* variables
+const (
+	envWorker = "XGB_DIST_WORKER"
+	envShard  = "XGB_DIST_SHARD"
+	envParams = "XGB_DIST_PARAMS"
+	envRounds = "XGB_DIST_ROUNDS"
+	envModel  = "XGB_DIST_MODEL"
+)
+
+// roundHook is called after every checkpointed round, tests use it to crash workers
+var roundHook func(iter int)
+
+// IsWorker report whether the process was started as a worker by Job.Run
+func IsWorker() bool {
+	return os.Getenv(envWorker) == "1"
+}
+
+type workerConfig struct {
+	shard  string
+	params xgboost.Params
+	rounds int
+	model  string
+}
+
+func workerConfigFromEnv() (*workerConfig, error) {
+	if !IsWorker() {
+		return nil, errors.New("not started as a worker")
+	}
+	cfg := &workerConfig{
+		shard: os.Getenv(envShard),
+		model: os.Getenv(envModel),
+	}
+	if cfg.shard == "" || cfg.model == "" {
+		return nil, fmt.Errorf("%s and %s must be set", envShard, envModel)
+	}
+	if err := json.Unmarshal([]byte(os.Getenv(envParams)), &cfg.params); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", envParams, err)
+	}
+	rounds, err := strconv.Atoi(os.Getenv(envRounds))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", envRounds, err)
+	}
+	cfg.rounds = rounds
+	return cfg, nil
+}
+
+// RunWorker train on the shard given by Job.Run, checkpointing every round. It resumes
+// from the latest checkpoint of the job, so a worker restarted after a failure rejoins
+// the others. The worker that ends up with rank 0 writes the model where Job.Run
+// collects it.
+func RunWorker() error {
+	cfg, err := workerConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	// load the shard before joining the job: once rabit runs distributed, xgboost
+	// would only read this worker's part of the file
+	dtrain, err := xgboost.DMatrixCreateFromFile(cfg.shard, 1)
+	if err != nil {
+		return err
+	}
+	defer dtrain.Free()
+
+	rabit.Init()
+	defer rabit.Finalize()
+
+	booster, err := xgboost.BoosterCreate([]*xgboost.DMatrix{dtrain})
+	if err != nil {
+		return err
+	}
+	defer booster.Free()
+	if err := booster.SetParams(cfg.params); err != nil {
+		return err
+	}
+
+	// one checkpoint is taken per round, so the version is the number of rounds done.
+	// A restarted worker gets the model of the others and carries on from there.
+	start, err := booster.LoadRabitCheckpoint()
+	if err != nil {
+		return err
+	}
+	if start > 0 {
+		rabit.TrackerPrint(fmt.Sprintf("rank %d resumed at round %d\n", rabit.Rank(), start))
+	}
+	for iter := start; iter < cfg.rounds; iter++ {
+		if err := booster.UpdateOneIter(iter, dtrain); err != nil {
+			return fmt.Errorf("round %d: %v", iter, err)
+		}
+		if err := booster.SaveRabitCheckpoint(); err != nil {
+			return fmt.Errorf("checkpoint round %d: %v", iter, err)
+		}
+		if roundHook != nil {
+			roundHook(iter)
+		}
+	}
+
+	if rabit.Rank() != 0 {
+		return nil
+	}
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return err
+	}
+	rabit.TrackerPrint(fmt.Sprintf("rank 0 finished %d rounds\n", cfg.rounds))
+	return ioutil.WriteFile(cfg.model, raw, 0644)
+}
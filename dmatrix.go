@@ -8,6 +8,7 @@ import "C"
 
 import (
 	"errors"
+	"fmt"
 	"github.com/liuhaoXD/xgboost-go/model"
 	"reflect"
 	"unsafe"
@@ -120,6 +121,97 @@ func (dMatrix *DMatrix) GetUIntInfo(field string) ([]uint32, error) {
 	return n, nil
 }
 
+// SetLabels set label of every row, one value per row
+func (dMatrix *DMatrix) SetLabels(labels []float32) error {
+	if err := dMatrix.checkRowInfo("label", len(labels), 1); err != nil {
+		return err
+	}
+	return dMatrix.SetFloatInfo("label", labels)
+}
+
+// GetLabels get label of every row
+func (dMatrix *DMatrix) GetLabels() ([]float32, error) {
+	return dMatrix.GetFloatInfo("label")
+}
+
+// SetWeights set instance weight of every row, one value per row
+func (dMatrix *DMatrix) SetWeights(weights []float32) error {
+	if err := dMatrix.checkRowInfo("weight", len(weights), 1); err != nil {
+		return err
+	}
+	return dMatrix.SetFloatInfo("weight", weights)
+}
+
+// GetWeights get instance weight of every row
+func (dMatrix *DMatrix) GetWeights() ([]float32, error) {
+	return dMatrix.GetFloatInfo("weight")
+}
+
+// SetBaseMargin set the initial prediction of every row. For multi-class models
+// numClass margins are expected per row (row-major), otherwise pass numClass <= 1.
+func (dMatrix *DMatrix) SetBaseMargin(margin []float32, numClass int) error {
+	if numClass < 1 {
+		numClass = 1
+	}
+	if err := dMatrix.checkRowInfo("base_margin", len(margin), numClass); err != nil {
+		return err
+	}
+	return dMatrix.SetFloatInfo("base_margin", margin)
+}
+
+// GetBaseMargin get the initial prediction of every row
+func (dMatrix *DMatrix) GetBaseMargin() ([]float32, error) {
+	return dMatrix.GetFloatInfo("base_margin")
+}
+
+// SetLabelBounds set the lower and upper label bound of every row, used by survival:aft
+func (dMatrix *DMatrix) SetLabelBounds(lower []float32, upper []float32) error {
+	if len(lower) != len(upper) {
+		return fmt.Errorf("label bounds length mismatch: %d lower bounds, %d upper bounds", len(lower), len(upper))
+	}
+	for i := range lower {
+		if lower[i] > upper[i] {
+			return fmt.Errorf("label bounds of row %d: lower bound %v is greater than upper bound %v", i, lower[i], upper[i])
+		}
+	}
+	if err := dMatrix.checkRowInfo("label_lower_bound", len(lower), 1); err != nil {
+		return err
+	}
+	if err := dMatrix.SetFloatInfo("label_lower_bound", lower); err != nil {
+		return err
+	}
+	return dMatrix.SetFloatInfo("label_upper_bound", upper)
+}
+
+// GetLabelBounds get the lower and upper label bound of every row
+func (dMatrix *DMatrix) GetLabelBounds() (lower []float32, upper []float32, err error) {
+	if lower, err = dMatrix.GetFloatInfo("label_lower_bound"); err != nil {
+		return nil, nil, err
+	}
+	if upper, err = dMatrix.GetFloatInfo("label_upper_bound"); err != nil {
+		return nil, nil, err
+	}
+	return lower, upper, nil
+}
+
+// checkRowInfo make sure a row info vector holds perRow values for every row
+func (dMatrix *DMatrix) checkRowInfo(field string, length int, perRow int) error {
+	rows, err := dMatrix.NumRow()
+	if err != nil {
+		return err
+	}
+	if length == 0 {
+		return fmt.Errorf("%s: no values given for %d rows", field, rows)
+	}
+	if expected := int(rows) * perRow; length != expected {
+		if perRow > 1 {
+			return fmt.Errorf("%s: got %d values, expected %d (%d rows x %d classes)", field, length, expected, rows, perRow)
+		}
+		return fmt.Errorf("%s: got %d values, expected one per row (%d rows)", field, length, rows)
+	}
+	return nil
+}
+
 func (dMatrix *DMatrix) Free() error {
 	return checkError(C.XGDMatrixFree(dMatrix.handle))
 }
@@ -155,14 +247,14 @@ func DMatrixCreateFromMat(data model.Matrix, missing float32) (*DMatrix, error)
 		}
 	}
 
-	dataC := make([]C.float, rows*cols)
+	buf := getFloatBuffer(rows * cols)
+	defer floatBuffers.Put(buf)
+	flat := *buf
 	for i, row := range data {
-		for j, v := range row {
-			dataC[i*cols+j] = C.float(v)
-		}
+		copy(flat[i*cols:], row)
 	}
 	var outHandle C.DMatrixHandle
-	ret := C.XGDMatrixCreateFromMat((*C.float)(unsafe.Pointer(&dataC[0])), C.bst_ulong(rows), C.bst_ulong(cols), C.float(missing), &outHandle)
+	ret := C.XGDMatrixCreateFromMat((*C.float)(unsafe.Pointer(&flat[0])), C.bst_ulong(rows), C.bst_ulong(cols), C.float(missing), &outHandle)
 	if err := checkError(ret); err != nil {
 		return nil, err
 	}
@@ -198,12 +290,57 @@ func DMatrixCreateFromDataIter(cacheInfo []byte) (*DMatrix, error) {
 	return nil, errors.New("DataIter not implemented yet")
 }
 
-func DMatrixCreateFromFromCSREx() (*DMatrix, error) {
-	return nil, errors.New("CSREx not implemented yet")
+// DMatrixCreateFromCSREx create a matrix from compressed sparse rows: the entries of row i
+// are indices[indptr[i]:indptr[i+1]] and data[indptr[i]:indptr[i+1]]. numCol 0 guesses
+// the number of columns from the indices.
+func DMatrixCreateFromCSREx(indptr []uint64, indices []uint32, data []float32, numCol int) (*DMatrix, error) {
+	if len(indptr) < 2 {
+		return nil, errors.New("missing data")
+	}
+	if len(indices) != len(data) {
+		return nil, fmt.Errorf("got %d indices for %d values", len(indices), len(data))
+	}
+	if indptr[len(indptr)-1] != uint64(len(data)) {
+		return nil, fmt.Errorf("indptr ends at %d, got %d values", indptr[len(indptr)-1], len(data))
+	}
+	// the slices are handed over without a copy where their layout matches the C types
+	indptrC := (*C.size_t)(unsafe.Pointer(&indptr[0]))
+	if unsafe.Sizeof(C.size_t(0)) != unsafe.Sizeof(indptr[0]) {
+		converted := make([]C.size_t, len(indptr))
+		for i, v := range indptr {
+			converted[i] = C.size_t(v)
+		}
+		indptrC = &converted[0]
+	}
+	// an empty matrix still needs valid pointers
+	if len(data) == 0 {
+		indices, data = []uint32{0}, []float32{0}
+	}
+	var outHandle C.DMatrixHandle
+	ret := C.XGDMatrixCreateFromCSREx(indptrC, (*C.uint)(unsafe.Pointer(&indices[0])), (*C.float)(unsafe.Pointer(&data[0])),
+		C.size_t(len(indptr)), C.size_t(indptr[len(indptr)-1]), C.size_t(numCol), &outHandle)
+	if err := checkError(ret); err != nil {
+		return nil, err
+	}
+	return &DMatrix{outHandle}, nil
 }
 
-func DMatrixSliceDMatrix() (*DMatrix, error) {
-	return nil, errors.New("slice DMatrix not implemented yet")
+// DMatrixSliceDMatrix create a new matrix holding the rows idxSet of dMatrix, in that order.
+// Labels, weights and base margins are sliced along; groups are not.
+func DMatrixSliceDMatrix(dMatrix *DMatrix, idxSet []int) (*DMatrix, error) {
+	if len(idxSet) == 0 {
+		return nil, errors.New("empty index set")
+	}
+	idxC := make([]C.int, len(idxSet))
+	for i, v := range idxSet {
+		idxC[i] = C.int(v)
+	}
+	var outHandle C.DMatrixHandle
+	ret := C.XGDMatrixSliceDMatrix(dMatrix.handle, (*C.int)(unsafe.Pointer(&idxC[0])), C.bst_ulong(len(idxC)), &outHandle)
+	if err := checkError(ret); err != nil {
+		return nil, err
+	}
+	return &DMatrix{outHandle}, nil
 }
 
 func DMatrixCreateFromDT() (*DMatrix, error) {
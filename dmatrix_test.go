@@ -52,3 +52,97 @@ func TestCreateFromMat(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestRowInfoSetters(t *testing.T) {
+	data := [][]float32{{1, 2}, {3, 4}, {5, 6}}
+
+	matrix, err := DMatrixCreateFromMat(model.Matrix(data), -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer matrix.Free()
+
+	if err := matrix.SetLabels([]float32{1, 0, 1}); err != nil {
+		t.Error(err)
+	}
+	if err := matrix.SetLabels([]float32{1, 0}); err == nil {
+		t.Error("expected error for short labels")
+	}
+	labels, err := matrix.GetLabels()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(labels) != 3 || labels[2] != 1 {
+		t.Errorf("Wrong labels %v returned", labels)
+	}
+
+	if err := matrix.SetWeights([]float32{0.5, 1, 2}); err != nil {
+		t.Error(err)
+	}
+	if err := matrix.SetWeights(nil); err == nil {
+		t.Error("expected error for empty weights")
+	}
+	weights, err := matrix.GetWeights()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(weights) != 3 || weights[0] != 0.5 {
+		t.Errorf("Wrong weights %v returned", weights)
+	}
+
+	if err := matrix.SetBaseMargin([]float32{0.1, 0.2, 0.3}, 1); err != nil {
+		t.Error(err)
+	}
+	if err := matrix.SetBaseMargin([]float32{0.1, 0.2, 0.3}, 2); err == nil {
+		t.Error("expected error for base margin without a value per class")
+	}
+	if err := matrix.SetBaseMargin([]float32{1, 2, 3, 4, 5, 6}, 2); err != nil {
+		t.Error(err)
+	}
+	margin, err := matrix.GetBaseMargin()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(margin) != 6 {
+		t.Errorf("Wrong base margin %v returned", margin)
+	}
+
+	if err := matrix.SetLabelBounds([]float32{1, 2, 3}, []float32{2, 2, 4}); err != nil {
+		t.Error(err)
+	}
+	if err := matrix.SetLabelBounds([]float32{1, 3, 3}, []float32{2, 2, 4}); err == nil {
+		t.Error("expected error for lower bound greater than upper bound")
+	}
+	lower, upper, err := matrix.GetLabelBounds()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(lower) != 3 || len(upper) != 3 || upper[2] != 4 {
+		t.Errorf("Wrong label bounds %v %v returned", lower, upper)
+	}
+}
+
+func TestCreateFromCSREx(t *testing.T) {
+	// [[1, _, 2], [_, _, _], [_, 3, _]]
+	matrix, err := DMatrixCreateFromCSREx([]uint64{0, 2, 2, 3}, []uint32{0, 2, 1}, []float32{1, 2, 3}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer matrix.Free()
+
+	rowCount, err := matrix.NumRow()
+	if err != nil {
+		t.Error(err)
+	}
+	colCount, err := matrix.NumCol()
+	if err != nil {
+		t.Error(err)
+	}
+	if rowCount != 3 || colCount != 3 {
+		t.Errorf("Wrong shape %dx%d returned", rowCount, colCount)
+	}
+
+	if _, err := DMatrixCreateFromCSREx([]uint64{0, 2}, []uint32{0}, []float32{1, 2}, 0); err == nil {
+		t.Error("expected error for mismatched indices and values")
+	}
+}
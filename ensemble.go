@@ -0,0 +1,343 @@
+package xgboost
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// Predictor predicts a DMatrix like Booster.Predict, implemented by Booster and
+// Ensemble
+type Predictor interface {
+	Predict(dMatrix *DMatrix, optionMask int, ntreeLimit uint) ([]float32, error)
+}
+
+var (
+	_ Predictor = (*Booster)(nil)
+	_ Predictor = (*Ensemble)(nil)
+)
+
+// EnsembleMethod is how an Ensemble combines the predictions of its members
+type EnsembleMethod int
+
+const (
+	// AverageMargin average the margins of the members by weight, then apply the
+	// output transform of their common objective
+	AverageMargin EnsembleMethod = iota
+	// AverageProbability average the transformed predictions of the members by weight,
+	// class probabilities for multiclass models, then take the most probable class for
+	// multi:softmax
+	AverageProbability
+	// Stack predict with a meta-model whose features are the transformed predictions of
+	// the members, every output of the first member followed by those of the next.
+	// Weights are ignored.
+	Stack
+)
+
+type ensembleMember struct {
+	booster  *Booster
+	model    *model.TreeModel
+	weight   float64
+	numGroup int
+	// objective is the objective of the member, for Stack where they may differ
+	objective string
+}
+
+// Ensemble combine the predictions of several models of the same features, such as
+// boosters trained per region. Members are boosters or parsed models; Predict predicts
+// a DMatrix like Booster.Predict when every member is a booster, PredictRows predicts
+// rows with any member. The ensemble does not own its members or meta-model.
+type Ensemble struct {
+	method       EnsembleMethod
+	members      []ensembleMember
+	meta         *Booster
+	metaFeatures int
+	numFeature   int
+	numGroup     int
+	objective    string
+}
+
+// NewEnsemble create an empty ensemble combining predictions by method
+func NewEnsemble(method EnsembleMethod) *Ensemble {
+	return &Ensemble{method: method}
+}
+
+// AddBooster add a member booster with a weight, checking that it has the features
+// of the other members, and their objective unless stacking
+func (e *Ensemble) AddBooster(booster *Booster, weight float64) error {
+	params, err := booster.LearnerParams()
+	if err != nil {
+		return err
+	}
+	numGroup := 1
+	if params.NumClass > 1 {
+		numGroup = params.NumClass
+	}
+	return e.add(ensembleMember{booster: booster, weight: weight, numGroup: numGroup, objective: params.Objective}, params.NumFeature)
+}
+
+// AddModel add a member parsed model with a weight, checked like AddBooster
+func (e *Ensemble) AddModel(m *model.TreeModel, weight float64) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	return e.add(ensembleMember{model: m, weight: weight, numGroup: m.NumGroup, objective: m.Objective}, m.NumFeature)
+}
+
+func (e *Ensemble) add(member ensembleMember, numFeature int) error {
+	if e.method != Stack && !(member.weight > 0) {
+		return fmt.Errorf("invalid weight %v, expected a positive weight", member.weight)
+	}
+	if len(e.members) == 0 {
+		e.numFeature, e.numGroup, e.objective = numFeature, member.numGroup, member.objective
+	}
+	if numFeature != e.numFeature {
+		return fmt.Errorf("member has %d features, the ensemble %d", numFeature, e.numFeature)
+	}
+	if e.method != Stack {
+		if member.objective != e.objective {
+			return fmt.Errorf("member objective %s differs from the ensemble's %s", member.objective, e.objective)
+		}
+		if member.numGroup != e.numGroup {
+			return fmt.Errorf("member has %d outputs, the ensemble %d", member.numGroup, e.numGroup)
+		}
+	}
+	e.members = append(e.members, member)
+	return nil
+}
+
+// SetMetaModel set the booster a Stack ensemble predicts with, trained on the
+// StackFeatures of the members
+func (e *Ensemble) SetMetaModel(meta *Booster) error {
+	if e.method != Stack {
+		return errors.New("only a Stack ensemble has a meta-model")
+	}
+	params, err := meta.LearnerParams()
+	if err != nil {
+		return err
+	}
+	e.meta, e.metaFeatures = meta, params.NumFeature
+	return nil
+}
+
+// Predict predict a DMatrix with every member, which must all be boosters, and combine
+// their predictions. Like Booster.Predict, optionMask 1 gives margins, of the meta-model
+// when stacking, and ntreeLimit limits the rounds of every member. Other options are not
+// supported.
+func (e *Ensemble) Predict(dMatrix *DMatrix, optionMask int, ntreeLimit uint) ([]float32, error) {
+	return e.predict(optionMask, func(member *ensembleMember) ([]float32, error) {
+		if member.booster == nil {
+			return nil, errors.New("a parsed model cannot predict a DMatrix, use PredictRows")
+		}
+		return member.booster.Predict(dMatrix, 1, ntreeLimit)
+	})
+}
+
+// PredictRows predict rows with every member and combine their predictions, like
+// Predict. NaN features are missing.
+func (e *Ensemble) PredictRows(rows model.Matrix, optionMask int, ntreeLimit uint) ([]float32, error) {
+	margins, free := e.rowMargins(rows, ntreeLimit)
+	defer free()
+	return e.predict(optionMask, margins)
+}
+
+// StackFeatures get the features of a DMatrix for the meta-model of a Stack ensemble,
+// to train it with
+func (e *Ensemble) StackFeatures(dMatrix *DMatrix, ntreeLimit uint) (model.Matrix, error) {
+	return e.stackFeatures(func(member *ensembleMember) ([]float32, error) {
+		if member.booster == nil {
+			return nil, errors.New("a parsed model cannot predict a DMatrix, use StackFeaturesRows")
+		}
+		return member.booster.Predict(dMatrix, 1, ntreeLimit)
+	})
+}
+
+// StackFeaturesRows get the features of rows for the meta-model of a Stack ensemble
+func (e *Ensemble) StackFeaturesRows(rows model.Matrix, ntreeLimit uint) (model.Matrix, error) {
+	margins, free := e.rowMargins(rows, ntreeLimit)
+	defer free()
+	return e.stackFeatures(margins)
+}
+
+// rowMargins get a function predicting the margins of rows with a member, and one
+// freeing the DMatrix it creates for booster members
+func (e *Ensemble) rowMargins(rows model.Matrix, ntreeLimit uint) (func(*ensembleMember) ([]float32, error), func()) {
+	var dm *DMatrix
+	margins := func(member *ensembleMember) ([]float32, error) {
+		if member.model != nil {
+			margins := make([]float32, 0, len(rows)*member.numGroup)
+			for _, row := range rows {
+				margins = append(margins, member.model.PredictMargin(row, int(ntreeLimit))...)
+			}
+			return margins, nil
+		}
+		if dm == nil {
+			var err error
+			if dm, err = DMatrixCreateFromMat(rows, float32(math.NaN())); err != nil {
+				return nil, err
+			}
+		}
+		return member.booster.Predict(dm, 1, ntreeLimit)
+	}
+	free := func() {
+		if dm != nil {
+			dm.Free()
+		}
+	}
+	return margins, free
+}
+
+// memberOutputs get the transformed predictions of every member, probabilities rather
+// than classes for multi:softmax, and the number of rows
+func (e *Ensemble) memberOutputs(margins func(*ensembleMember) ([]float32, error)) ([][]float32, int, error) {
+	outputs, numRows, err := e.memberMargins(margins)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range e.members {
+		member := &e.members[i]
+		t, err := model.ObjectiveTransform(member.objective)
+		if err != nil {
+			return nil, 0, fmt.Errorf("member %d: %v", i, err)
+		}
+		if t == model.TransformArgmax {
+			t = model.TransformSoftmax
+		}
+		for row := 0; row < numRows; row++ {
+			t.Apply(outputs[i][row*member.numGroup : (row+1)*member.numGroup])
+		}
+	}
+	return outputs, numRows, nil
+}
+
+// memberMargins get the margins of every member and the number of rows
+func (e *Ensemble) memberMargins(margins func(*ensembleMember) ([]float32, error)) ([][]float32, int, error) {
+	if len(e.members) == 0 {
+		return nil, 0, errors.New("empty ensemble")
+	}
+	outputs := make([][]float32, len(e.members))
+	numRows := 0
+	for i := range e.members {
+		member := &e.members[i]
+		var err error
+		if outputs[i], err = margins(member); err != nil {
+			return nil, 0, fmt.Errorf("member %d: %v", i, err)
+		}
+		if i == 0 {
+			numRows = len(outputs[i]) / member.numGroup
+		}
+		if len(outputs[i]) != numRows*member.numGroup {
+			return nil, 0, fmt.Errorf("member %d predicted %d values for %d rows", i, len(outputs[i]), numRows)
+		}
+	}
+	return outputs, numRows, nil
+}
+
+func (e *Ensemble) stackFeatures(margins func(*ensembleMember) ([]float32, error)) (model.Matrix, error) {
+	if e.method != Stack {
+		return nil, errors.New("only a Stack ensemble has stack features")
+	}
+	outputs, numRows, err := e.memberOutputs(margins)
+	if err != nil {
+		return nil, err
+	}
+	width := 0
+	for i := range e.members {
+		width += e.members[i].numGroup
+	}
+	features := make(model.Matrix, numRows)
+	for row := range features {
+		features[row] = make([]float32, 0, width)
+		for i := range e.members {
+			g := e.members[i].numGroup
+			features[row] = append(features[row], outputs[i][row*g:(row+1)*g]...)
+		}
+	}
+	return features, nil
+}
+
+func (e *Ensemble) predict(optionMask int, margins func(*ensembleMember) ([]float32, error)) ([]float32, error) {
+	if optionMask != 0 && optionMask != 1 {
+		return nil, fmt.Errorf("unsupported option mask %d, an ensemble predicts with 0 or 1", optionMask)
+	}
+	outputMargin := optionMask == 1
+
+	switch e.method {
+	case AverageMargin:
+		outputs, numRows, err := e.memberMargins(margins)
+		if err != nil {
+			return nil, err
+		}
+		average := e.average(outputs)
+		if outputMargin {
+			return average, nil
+		}
+		t, err := model.ObjectiveTransform(e.objective)
+		if err != nil {
+			return nil, err
+		}
+		preds := make([]float32, 0, len(average))
+		for row := 0; row < numRows; row++ {
+			preds = append(preds, t.Apply(average[row*e.numGroup:(row+1)*e.numGroup])...)
+		}
+		return preds, nil
+
+	case AverageProbability:
+		if outputMargin {
+			return nil, errors.New("an ensemble averaging probabilities has no margin")
+		}
+		outputs, numRows, err := e.memberOutputs(margins)
+		if err != nil {
+			return nil, err
+		}
+		average := e.average(outputs)
+		if e.objective != "multi:softmax" {
+			return average, nil
+		}
+		preds := make([]float32, numRows)
+		for row := range preds {
+			preds[row] = model.TransformArgmax.Apply(average[row*e.numGroup : (row+1)*e.numGroup])[0]
+		}
+		return preds, nil
+
+	case Stack:
+		if e.meta == nil {
+			return nil, errors.New("no meta-model, use SetMetaModel")
+		}
+		features, err := e.stackFeatures(margins)
+		if err != nil {
+			return nil, err
+		}
+		if len(features) > 0 && len(features[0]) != e.metaFeatures {
+			return nil, fmt.Errorf("meta-model has %d features, the members %d outputs", e.metaFeatures, len(features[0]))
+		}
+		dm, err := DMatrixCreateFromMat(features, float32(math.NaN()))
+		if err != nil {
+			return nil, err
+		}
+		defer dm.Free()
+		return e.meta.Predict(dm, optionMask, 0)
+	}
+	return nil, fmt.Errorf("unknown ensemble method %d", e.method)
+}
+
+// average get the weighted average of the outputs of the members
+func (e *Ensemble) average(outputs [][]float32) []float32 {
+	var total float64
+	for i := range e.members {
+		total += e.members[i].weight
+	}
+	sums := make([]float64, len(outputs[0]))
+	for i, output := range outputs {
+		for j, v := range output {
+			sums[j] += e.members[i].weight * float64(v)
+		}
+	}
+	average := make([]float32, len(sums))
+	for j, sum := range sums {
+		average[j] = float32(sum / total)
+	}
+	return average
+}
@@ -0,0 +1,233 @@
+package xgboost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// trainRegions train a booster on each half of the rows, as if on two regions
+func trainRegions(t *testing.T, rows model.Matrix, labels []float32, params Params) []*Booster {
+	var boosters []*Booster
+	for half := 0; half < 2; half++ {
+		var regionRows model.Matrix
+		var regionLabels []float32
+		for i := half; i < len(rows); i += 2 {
+			regionRows = append(regionRows, rows[i])
+			regionLabels = append(regionLabels, labels[i])
+		}
+		dm, err := DMatrixCreateFromMat(regionRows, float32(math.NaN()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := dm.SetLabels(regionLabels); err != nil {
+			t.Fatal(err)
+		}
+		booster, err := Train(params, dm, 4+half)
+		dm.Free()
+		if err != nil {
+			t.Fatal(err)
+		}
+		boosters = append(boosters, booster)
+	}
+	return boosters
+}
+
+func ensembleTestData(t *testing.T, numClass int) (*DMatrix, model.Matrix, []float32) {
+	dm, rows := shapTestData(t, numClass)
+	labels, err := dm.GetLabels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dm, rows, labels
+}
+
+func TestEnsembleAverage(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		numClass int
+		method   EnsembleMethod
+		params   Params
+	}{
+		{"binary margin", 2, AverageMargin, Params{"objective": "binary:logistic"}},
+		{"binary probability", 2, AverageProbability, Params{"objective": "binary:logistic"}},
+		{"multiclass margin", 3, AverageMargin, Params{"objective": "multi:softprob", "num_class": "3"}},
+		{"multiclass probability", 3, AverageProbability, Params{"objective": "multi:softprob", "num_class": "3"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			dm, rows, labels := ensembleTestData(t, c.numClass)
+			defer dm.Free()
+			c.params["max_depth"] = "3"
+			c.params["silent"] = "1"
+			boosters := trainRegions(t, rows, labels, c.params)
+			for _, booster := range boosters {
+				defer booster.Free()
+			}
+			weights := []float64{1, 3}
+
+			ensemble := NewEnsemble(c.method)
+			var want []float32
+			for i, booster := range boosters {
+				if err := ensemble.AddBooster(booster, weights[i]); err != nil {
+					t.Fatal(err)
+				}
+				mask := 1
+				if c.method == AverageProbability {
+					mask = 0
+				}
+				preds, err := booster.Predict(dm, mask, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want == nil {
+					want = make([]float32, len(preds))
+				}
+				for j, v := range preds {
+					want[j] += float32(weights[i]/4) * v
+				}
+			}
+			if c.method == AverageMargin {
+				perRow := len(want) / len(rows)
+				for row := range rows {
+					if _, err := model.Transform(c.params["objective"], want[row*perRow:(row+1)*perRow]); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			got, err := ensemble.Predict(dm, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEnsemble(t, got, want)
+
+			// the same ensemble of a booster and a parsed model predicts rows alike
+			m, err := boosters[1].TreeModel()
+			if err != nil {
+				t.Fatal(err)
+			}
+			mixed := NewEnsemble(c.method)
+			if err := mixed.AddBooster(boosters[0], weights[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := mixed.AddModel(m, weights[1]); err != nil {
+				t.Fatal(err)
+			}
+			got, err = mixed.PredictRows(rows, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkEnsemble(t, got, want)
+			if _, err := mixed.Predict(dm, 0, 0); err == nil {
+				t.Error("expected an error predicting a DMatrix with a parsed model")
+			}
+		})
+	}
+}
+
+func checkEnsemble(t *testing.T, got, want []float32) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d predictions, expected %d", len(got), len(want))
+	}
+	for i := range want {
+		if !closeEnough(float64(got[i]), float64(want[i])) {
+			t.Fatalf("prediction %d: got %v, expected %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestEnsembleStack(t *testing.T) {
+	dm, rows, labels := ensembleTestData(t, 1)
+	defer dm.Free()
+	boosters := trainRegions(t, rows, labels, Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"})
+	for _, booster := range boosters {
+		defer booster.Free()
+	}
+	ensemble := NewEnsemble(Stack)
+	for _, booster := range boosters {
+		if err := ensemble.AddBooster(booster, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ensemble.Predict(dm, 0, 0); err == nil {
+		t.Error("expected an error stacking without a meta-model")
+	}
+
+	features, err := ensemble.StackFeatures(dm, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(features) != len(rows) || len(features[0]) != 2 {
+		t.Fatalf("got stack features of %d rows of %d", len(features), len(features[0]))
+	}
+	metaData, err := DMatrixCreateFromMat(features, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metaData.Free()
+	if err := metaData.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := Train(Params{"booster": "gblinear", "silent": "1"}, metaData, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer meta.Free()
+	if err := ensemble.SetMetaModel(meta); err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := meta.Predict(metaData, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ensemble.Predict(dm, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkEnsemble(t, got, want)
+	if got, err = ensemble.PredictRows(rows, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	checkEnsemble(t, got, want)
+}
+
+func TestEnsembleCompatibility(t *testing.T) {
+	dm, rows, labels := ensembleTestData(t, 2)
+	defer dm.Free()
+	params := Params{"objective": "binary:logistic", "max_depth": "2", "silent": "1"}
+	boosters := trainRegions(t, rows, labels, params)
+	for _, booster := range boosters {
+		defer booster.Free()
+	}
+	m, err := boosters[0].TreeModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ensemble := NewEnsemble(AverageMargin)
+	if err := ensemble.AddBooster(boosters[0], 0); err == nil {
+		t.Error("expected an error for a zero weight")
+	}
+	if err := ensemble.AddBooster(boosters[0], 1); err != nil {
+		t.Fatal(err)
+	}
+	narrow := *m
+	narrow.NumFeature = 2
+	if err := ensemble.AddModel(&narrow, 1); err == nil {
+		t.Error("expected an error for a member of fewer features")
+	}
+	regression := *m
+	regression.Objective = "reg:logistic"
+	if err := ensemble.AddModel(&regression, 1); err == nil {
+		t.Error("expected an error for a member of another objective")
+	}
+	if _, err := ensemble.Predict(dm, 4, 0); err == nil {
+		t.Error("expected an error for contributions")
+	}
+	if err := ensemble.SetMetaModel(boosters[1]); err == nil {
+		t.Error("expected an error setting the meta-model of an average")
+	}
+}
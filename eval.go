@@ -0,0 +1,66 @@
+package xgboost
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EvalResult is a single "<data>-<metric>:<value>" entry of an EvalOneIter result
+type EvalResult struct {
+	Data   string
+	Metric string
+	Value  float64
+}
+
+// Name get the "<data>-<metric>" name of the entry
+func (r EvalResult) Name() string {
+	return r.Data + "-" + r.Metric
+}
+
+// ParseEvalResult parse the result of EvalOneIter, e.g. "[3]\ttrain-rmse:0.21\ttest-rmse:0.35",
+// given the evals names passed to EvalOneIter. Names and metrics may both contain
+// dashes, so every entry is split after the longest of names it starts with. Entries
+// are returned in the order they appear.
+func ParseEvalResult(result string, names []string) ([]EvalResult, error) {
+	fields := strings.Fields(result)
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "[") {
+		fields = fields[1:]
+	}
+	entries := make([]EvalResult, 0, len(fields))
+	for _, field := range fields {
+		colon := strings.LastIndex(field, ":")
+		if colon < 0 {
+			return nil, fmt.Errorf("malformed eval entry %q", field)
+		}
+		data := ""
+		for _, name := range names {
+			if len(name) > len(data) && strings.HasPrefix(field[:colon], name+"-") {
+				data = name
+			}
+		}
+		if data == "" {
+			return nil, fmt.Errorf("eval entry %q is not of any of %q", field, names)
+		}
+		value, err := strconv.ParseFloat(field[colon+1:], 64)
+		if err != nil {
+			return nil, fmt.Errorf("malformed eval entry %q: %v", field, err)
+		}
+		entries = append(entries, EvalResult{
+			Data:   data,
+			Metric: field[len(data)+1 : colon],
+			Value:  value,
+		})
+	}
+	return entries, nil
+}
+
+// MetricMaximize report whether a larger value of metric is better, as for auc or ndcg@5
+func MetricMaximize(metric string) bool {
+	for _, prefix := range []string{"auc", "aucpr", "map", "ndcg", "pre"} {
+		if metric == prefix || strings.HasPrefix(metric, prefix+"@") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package xgboost
+
+import "testing"
+
+func TestParseEvalResult(t *testing.T) {
+	entries, err := ParseEvalResult("[12]\ttrain-rmse:0.215\ttest-ndcg@5-:0.75", []string{"train", "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Name() != "train-rmse" || entries[0].Value != 0.215 {
+		t.Errorf("wrong first entry %+v", entries[0])
+	}
+	if entries[1].Data != "test" || entries[1].Metric != "ndcg@5-" || entries[1].Value != 0.75 {
+		t.Errorf("wrong second entry %+v", entries[1])
+	}
+	if !MetricMaximize(entries[1].Metric) || MetricMaximize(entries[0].Metric) {
+		t.Error("wrong metric direction")
+	}
+
+	// dashed data names, one the prefix of another
+	entries, err = ParseEvalResult("[0]\tvalid-2020-auc:0.9\tvalid-2020-01-ndcg@3-:0.5", []string{"valid-2020", "valid-2020-01"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Data != "valid-2020" || entries[0].Metric != "auc" {
+		t.Errorf("wrong first dashed entry %+v", entries[0])
+	}
+	if entries[1].Data != "valid-2020-01" || entries[1].Metric != "ndcg@3-" || entries[1].Value != 0.5 {
+		t.Errorf("wrong second dashed entry %+v", entries[1])
+	}
+
+	if _, err := ParseEvalResult("[0]\ttrain-rmse", []string{"train"}); err == nil {
+		t.Error("expected error for entry without value")
+	}
+	if _, err := ParseEvalResult("[0]\ttrain-rmse:0.5", []string{"test"}); err == nil {
+		t.Error("expected error for entry of an unknown data name")
+	}
+}
@@ -0,0 +1,80 @@
+package xgboost
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// importanceTypes are the importance types understood by FeatureImportance
+var importanceTypes = []string{"weight", "gain", "cover", "total_gain", "total_cover"}
+
+// FeatureImportance compute the importance of every feature used in a split, keyed by
+// feature index. importanceType is one of
+//   - weight: the number of splits on the feature
+//   - gain, cover: the average gain or cover of its splits
+//   - total_gain, total_cover: the total gain or cover of its splits
+func (booster *Booster) FeatureImportance(importanceType string) (map[int]float64, error) {
+	if !contains(importanceTypes, importanceType) {
+		return nil, fmt.Errorf("unknown importance type %q, expected one of %s", importanceType, strings.Join(importanceTypes, ", "))
+	}
+	trees, err := booster.DumpModel("", true)
+	if err != nil {
+		return nil, err
+	}
+	return dumpImportance(trees, importanceType)
+}
+
+// dumpImportance compute feature importance from a text dump with stats, where splits
+// look like "3:[f2<0.5] yes=7,no=8,missing=7,gain=10.5,cover=30"
+func dumpImportance(trees []string, importanceType string) (map[int]float64, error) {
+	counts := map[int]float64{}
+	totals := map[int]float64{}
+	stat := strings.TrimPrefix(importanceType, "total_")
+	for _, tree := range trees {
+		for _, line := range strings.Split(tree, "\n") {
+			open := strings.Index(line, "[")
+			end := strings.Index(line, "]")
+			if open < 0 || end < open {
+				continue
+			}
+			split := line[open+1 : end]
+			if i := strings.Index(split, "<"); i >= 0 {
+				split = split[:i]
+			}
+			feature, err := strconv.Atoi(strings.TrimPrefix(split, "f"))
+			if err != nil {
+				return nil, fmt.Errorf("malformed split %q: %v", line, err)
+			}
+			counts[feature]++
+			if stat == "weight" {
+				continue
+			}
+			value, err := dumpStat(line[end+1:], stat)
+			if err != nil {
+				return nil, err
+			}
+			totals[feature] += value
+		}
+	}
+
+	switch importanceType {
+	case "weight":
+		return counts, nil
+	case "gain", "cover":
+		for feature, total := range totals {
+			totals[feature] = total / counts[feature]
+		}
+	}
+	return totals, nil
+}
+
+// dumpStat get a "name=value" statistic of a dumped node
+func dumpStat(node string, name string) (float64, error) {
+	for _, field := range strings.FieldsFunc(node, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if strings.HasPrefix(field, name+"=") {
+			return strconv.ParseFloat(field[len(name)+1:], 64)
+		}
+	}
+	return 0, fmt.Errorf("node %q has no %s, dump the model with stats", node, name)
+}
@@ -0,0 +1,34 @@
+package xgboost
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDumpImportance(t *testing.T) {
+	trees := []string{
+		"0:[f0<2.5] yes=1,no=2,missing=1,gain=10,cover=20\n\t1:leaf=0.1,cover=8\n\t2:[f2<1] yes=3,no=4,missing=3,gain=4,cover=12\n\t\t3:leaf=0.2,cover=6\n\t\t4:leaf=0.3,cover=6\n",
+		"0:[f0<4.5] yes=1,no=2,missing=2,gain=6,cover=20\n\t1:leaf=-0.1,cover=10\n\t2:leaf=0.1,cover=10\n",
+	}
+	for _, c := range []struct {
+		importanceType string
+		want           map[int]float64
+	}{
+		{"weight", map[int]float64{0: 2, 2: 1}},
+		{"gain", map[int]float64{0: 8, 2: 4}},
+		{"total_gain", map[int]float64{0: 16, 2: 4}},
+		{"cover", map[int]float64{0: 20, 2: 12}},
+		{"total_cover", map[int]float64{0: 40, 2: 12}},
+	} {
+		got, err := dumpImportance(trees, c.importanceType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %v, expected %v", c.importanceType, got, c.want)
+		}
+	}
+	if _, err := dumpImportance([]string{"0:[f0<1] yes=1,no=2,missing=1\n"}, "gain"); err == nil {
+		t.Error("expected an error for a dump without stats")
+	}
+}
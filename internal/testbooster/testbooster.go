@@ -0,0 +1,114 @@
+// Package testbooster trains small boosters for the tests comparing exported models
+// with libxgboost. It is apart from testmodel so that tests of pure Go code do not
+// need libxgboost.
+package testbooster
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// Rows are 80 rows of 3 features, every 6th with a missing feature
+var Rows = rows()
+
+func rows() model.Matrix {
+	rows := make(model.Matrix, 80)
+	for i := range rows {
+		rows[i] = []float32{float32(i % 9), float32(i%5) - 2, float32((i * 7) % 13)}
+		if i%6 == 0 {
+			rows[i][i%3] = float32(math.NaN())
+		}
+	}
+	return rows
+}
+
+// Train train a booster of objective for 4 rounds on Rows, labelled with numClass
+// classes or a regression target when numClass is 1, and return its tree model and
+// predictions of Rows
+func Train(t *testing.T, objective string, numClass int) (*model.TreeModel, []float32) {
+	labels := make([]float32, len(Rows))
+	for i := range labels {
+		labels[i] = float32(i % numClass)
+		if numClass == 1 {
+			labels[i] = float32(i%9) + 1
+		}
+	}
+	dm, err := xgboost.DMatrixCreateFromMat(Rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dm.Free()
+	if err := dm.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	params := xgboost.Params{"objective": objective, "max_depth": "3", "silent": "1"}
+	if numClass > 2 {
+		params["num_class"] = strconv.Itoa(numClass)
+	}
+	booster, err := xgboost.Train(params, dm, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	preds, err := booster.Predict(dm, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := booster.TreeModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, preds
+}
+
+// SaveRegression train a regression booster for rounds rounds on 50 rows of 3
+// features, set attrs on it and save it at path. The caller frees the booster.
+func SaveRegression(t *testing.T, path string, rounds int, attrs map[string]string) *xgboost.Booster {
+	data := make(model.Matrix, 50)
+	labels := make([]float32, len(data))
+	for i := range data {
+		data[i] = []float32{float32(i), float32(i % 7), float32(i % 3)}
+		labels[i] = float32(i*2 + i%7)
+	}
+	dtrain, err := xgboost.DMatrixCreateFromMat(data, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtrain.Free()
+	if err := dtrain.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	booster, err := xgboost.Train(xgboost.Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"}, dtrain, rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range attrs {
+		if err := booster.SetAttr(key, value); err != nil {
+			booster.Free()
+			t.Fatal(err)
+		}
+	}
+	if err := booster.SaveModel(path); err != nil {
+		booster.Free()
+		t.Fatal(err)
+	}
+	return booster
+}
+
+// Predict predict rows, NaN for a missing feature, with booster
+func Predict(t *testing.T, booster *xgboost.Booster, rows model.Matrix) []float32 {
+	matrix, err := xgboost.DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer matrix.Free()
+	preds, err := booster.Predict(matrix, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return preds
+}
@@ -0,0 +1,39 @@
+// Package testmodel holds a small tree model shared by the tests of the packages
+// exporting and rendering tree models.
+package testmodel
+
+import (
+	"math"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// Dumps are the JSON dumps of three trees with their stats: a tree of depth 2 on
+// features 0 and 1, a stump on feature 2 with a tiny leaf, and a single leaf
+var Dumps = []string{
+	`{"nodeid": 0, "split": 0, "split_condition": 2.5, "yes": 1, "no": 2, "missing": 2, "gain": 40, "cover": 100, "children": [
+		{"nodeid": 1, "split": 1, "split_condition": -1, "yes": 3, "no": 4, "missing": 3, "gain": 10, "cover": 60, "children": [
+			{"nodeid": 3, "leaf": 0.4, "cover": 20}, {"nodeid": 4, "leaf": -0.2, "cover": 40}]},
+		{"nodeid": 2, "leaf": 0.7, "cover": 40}]}`,
+	`{"nodeid": 0, "split": 2, "split_condition": 0.5, "yes": 1, "no": 2, "missing": 1, "gain": 8, "cover": 100, "children": [
+		{"nodeid": 1, "leaf": -0.3, "cover": 70}, {"nodeid": 2, "leaf": 1e-07, "cover": 30}]}`,
+	`{"nodeid": 0, "leaf": 0.05, "cover": 100}`,
+}
+
+// Rows are rows of the 3 features of the model, NaN features are missing
+var Rows = model.Matrix{{1, 0, 0}, {1, -2, 1}, {3, 0, 1}, {float32(math.NaN()), -3, float32(math.NaN())}, {2.5, -1, 0.5}}
+
+// Model get the trees of Dumps as a model of objective, dealt round-robin into
+// numGroup groups
+func Model(t *testing.T, objective string, numGroup int) *model.TreeModel {
+	trees, err := model.ParseTrees(Dumps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := make([]int, len(trees))
+	for i := range groups {
+		groups[i] = i % numGroup
+	}
+	return &model.TreeModel{Trees: trees, TreeGroups: groups, NumGroup: numGroup, NumFeature: 3, BaseMargin: 0.25, Objective: objective}
+}
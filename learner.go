@@ -0,0 +1,166 @@
+package xgboost
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// LearnerParams are the learner parameters saved at the start of a binary model
+type LearnerParams struct {
+	// BaseScore is the global bias, already transformed to a margin by the objective
+	BaseScore  float32
+	NumFeature int
+	// NumClass is 0 for models that are not multiclass
+	NumClass  int
+	Objective string
+	Booster   string
+	// TreeGroups is the output group, i.e. the class, of every tree of a tree booster
+	TreeGroups []int
+}
+
+const (
+	// learnerParamSize is sizeof(LearnerModelParam): base_score, num_feature, num_class,
+	// contain_extra_attrs, contain_eval_metrics and 29 reserved ints
+	learnerParamSize = 4 * 34
+	// gbtreeParamSize is sizeof(GBTreeModelParam): num_trees, num_roots, num_feature,
+	// a pad, the int64 num_pbuffer, num_output_group, size_leaf_vector and 32 reserved ints
+	gbtreeParamSize = 4*4 + 8 + 4*2 + 4*32
+	// treeParamSize is sizeof(TreeParam): num_roots, num_nodes, num_deleted, max_depth,
+	// num_feature, size_leaf_vector and 31 reserved ints
+	treeParamSize = 4 * 37
+	// treeNodeSize and treeStatSize are the sizes of a saved tree node and its stats
+	treeNodeSize = 20
+	treeStatSize = 16
+)
+
+// LearnerParams read the learner parameters of the model. The C API has no accessor
+// for them, so they are parsed from the raw model.
+func (booster *Booster) LearnerParams() (*LearnerParams, error) {
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return nil, err
+	}
+	return parseLearnerParams(raw)
+}
+
+func parseLearnerParams(raw []byte) (*LearnerParams, error) {
+	params, _, rest, err := splitLearner(raw)
+	if err != nil {
+		return nil, err
+	}
+	if params.Booster == "gbtree" || params.Booster == "dart" {
+		saved, err := readGBTree(rest)
+		if err != nil {
+			return nil, fmt.Errorf("read trees: %v", err)
+		}
+		params.TreeGroups = saved.groups
+	}
+	return params, nil
+}
+
+// splitLearner read the learner parameters of a raw model, and split it into the
+// learner part they are read from and the saved booster that follows
+func splitLearner(raw []byte) (*LearnerParams, []byte, []byte, error) {
+	start := 0
+	if bytes.HasPrefix(raw, []byte("binf")) {
+		start = 4
+	}
+	if len(raw) < start+learnerParamSize {
+		return nil, nil, nil, errors.New("model too short for learner params")
+	}
+	params := &LearnerParams{
+		BaseScore:  math.Float32frombits(binary.LittleEndian.Uint32(raw[start:])),
+		NumFeature: int(binary.LittleEndian.Uint32(raw[start+4:])),
+		NumClass:   int(int32(binary.LittleEndian.Uint32(raw[start+8:]))),
+	}
+	rest := raw[start+learnerParamSize:]
+	var err error
+	if params.Objective, rest, err = readModelString(rest); err != nil {
+		return nil, nil, nil, fmt.Errorf("read objective: %v", err)
+	}
+	if params.Booster, rest, err = readModelString(rest); err != nil {
+		return nil, nil, nil, fmt.Errorf("read booster: %v", err)
+	}
+	return params, raw[:len(raw)-len(rest)], rest, nil
+}
+
+// savedGBTree is a gbtree model as saved, split into its trees
+type savedGBTree struct {
+	// param is the GBTreeModelParam
+	param []byte
+	trees [][]byte
+	// groups is the tree_info saved after the trees
+	groups []int
+	// rest is whatever the learner saved after the booster, attributes and metrics
+	rest []byte
+}
+
+// readGBTree split a saved gbtree model into its trees
+func readGBTree(raw []byte) (*savedGBTree, error) {
+	if len(raw) < gbtreeParamSize {
+		return nil, errors.New("model truncated")
+	}
+	numTrees := int(int32(binary.LittleEndian.Uint32(raw)))
+	saved := &savedGBTree{param: raw[:gbtreeParamSize], trees: make([][]byte, numTrees)}
+	raw = raw[gbtreeParamSize:]
+	for i := 0; i < numTrees; i++ {
+		if len(raw) < treeParamSize {
+			return nil, errors.New("model truncated")
+		}
+		numNodes := int(int32(binary.LittleEndian.Uint32(raw[4:])))
+		leafVector := binary.LittleEndian.Uint32(raw[20:]) != 0
+		size := treeParamSize + numNodes*(treeNodeSize+treeStatSize)
+		if numNodes < 0 || len(raw) < size {
+			return nil, errors.New("model truncated")
+		}
+		if leafVector {
+			if len(raw) < size+8 || binary.LittleEndian.Uint64(raw[size:]) > uint64(len(raw)-size-8)/4 {
+				return nil, errors.New("model truncated")
+			}
+			size += 8 + 4*int(binary.LittleEndian.Uint64(raw[size:]))
+		}
+		saved.trees[i] = raw[:size]
+		raw = raw[size:]
+	}
+	if len(raw) < 4*numTrees {
+		return nil, errors.New("model truncated")
+	}
+	saved.groups = make([]int, numTrees)
+	for i := range saved.groups {
+		saved.groups[i] = int(int32(binary.LittleEndian.Uint32(raw[4*i:])))
+	}
+	saved.rest = raw[4*numTrees:]
+	return saved, nil
+}
+
+// bytes save the model with only the trees of the given indexes
+func (saved *savedGBTree) bytes(keep []int) []byte {
+	var b bytes.Buffer
+	param := append([]byte{}, saved.param...)
+	binary.LittleEndian.PutUint32(param, uint32(len(keep)))
+	b.Write(param)
+	for _, i := range keep {
+		b.Write(saved.trees[i])
+	}
+	for _, i := range keep {
+		binary.Write(&b, binary.LittleEndian, int32(saved.groups[i]))
+	}
+	b.Write(saved.rest)
+	return b.Bytes()
+}
+
+// readModelString read a string saved by dmlc::Stream, a uint64 length and the bytes
+func readModelString(raw []byte) (string, []byte, error) {
+	if len(raw) < 8 {
+		return "", nil, errors.New("model truncated")
+	}
+	length := binary.LittleEndian.Uint64(raw)
+	raw = raw[8:]
+	if length > uint64(len(raw)) {
+		return "", nil, errors.New("model truncated")
+	}
+	return string(raw[:length]), raw[length:], nil
+}
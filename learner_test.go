@@ -0,0 +1,81 @@
+package xgboost
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// testModelRaw build a raw multiclass gbtree model of two trees followed by tail
+func testModelRaw(tail string) []byte {
+	var raw bytes.Buffer
+	ints := make([]int32, 33)
+	ints[0], ints[1] = 3, 5
+	binary.Write(&raw, binary.LittleEndian, math.Float32bits(0.25))
+	binary.Write(&raw, binary.LittleEndian, ints)
+	for _, s := range []string{"multi:softprob", "gbtree"} {
+		binary.Write(&raw, binary.LittleEndian, uint64(len(s)))
+		raw.WriteString(s)
+	}
+	// two trees of 3 and 1 nodes, the second with a leaf vector
+	binary.Write(&raw, binary.LittleEndian, make([]byte, gbtreeParamSize))
+	binary.LittleEndian.PutUint32(raw.Bytes()[raw.Len()-gbtreeParamSize:], 2)
+	for _, tree := range []struct{ nodes, leafVector int32 }{{3, 0}, {1, 1}} {
+		treeParam := make([]int32, treeParamSize/4)
+		treeParam[1], treeParam[5] = tree.nodes, tree.leafVector
+		binary.Write(&raw, binary.LittleEndian, treeParam)
+		raw.Write(make([]byte, int(tree.nodes)*(treeNodeSize+treeStatSize)))
+		if tree.leafVector != 0 {
+			binary.Write(&raw, binary.LittleEndian, uint64(2))
+			binary.Write(&raw, binary.LittleEndian, []float32{1, 2})
+		}
+	}
+	binary.Write(&raw, binary.LittleEndian, []int32{0, 4})
+	raw.WriteString(tail)
+	return raw.Bytes()
+}
+
+func TestParseLearnerParams(t *testing.T) {
+	raw := testModelRaw("")
+	params, err := parseLearnerParams(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LearnerParams{BaseScore: 0.25, NumFeature: 3, NumClass: 5, Objective: "multi:softprob", Booster: "gbtree", TreeGroups: []int{0, 4}}
+	if !reflect.DeepEqual(*params, want) {
+		t.Errorf("got %+v, expected %+v", *params, want)
+	}
+	if _, err := parseLearnerParams(raw[:len(raw)-4]); err == nil {
+		t.Error("expected an error for a truncated model")
+	}
+}
+
+func TestSelectSavedTrees(t *testing.T) {
+	raw := testModelRaw("attributes")
+	_, learner, rest, err := splitLearner(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := readGBTree(rest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(saved.bytes([]int{0, 1}), rest) {
+		t.Error("saving every tree does not give the model back")
+	}
+
+	selected := append(append([]byte{}, learner...), saved.bytes([]int{1})...)
+	params, err := parseLearnerParams(selected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params.TreeGroups, []int{4}) {
+		t.Errorf("got tree groups %v, expected [4]", params.TreeGroups)
+	}
+	_, _, rest, _ = splitLearner(selected)
+	if saved, err = readGBTree(rest); err != nil || string(saved.rest) != "attributes" {
+		t.Errorf("got %q after the trees, %v", saved.rest, err)
+	}
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Diagnostics describe the shape of a tree model, to sanity check it before use
+type Diagnostics struct {
+	NumTrees int `json:"num_trees"`
+	// Trees describe every tree
+	Trees []TreeStats `json:"trees"`
+	// DepthCounts count the trees of every depth, indexed by depth
+	DepthCounts []int `json:"depth_counts"`
+	// Features describe the splits on every feature used, by feature index
+	Features map[int]*FeatureStats `json:"features"`
+	// DeadFeatures are the features below NumFeature never split on
+	DeadFeatures []int     `json:"dead_features"`
+	Anomalies    []Anomaly `json:"anomalies"`
+}
+
+// TreeStats describe a tree
+type TreeStats struct {
+	Depth  int `json:"depth"`
+	Leaves int `json:"leaves"`
+	// Features are the features the tree splits on, in increasing order
+	Features []int `json:"features"`
+}
+
+// FeatureStats describe the splits on a feature
+type FeatureStats struct {
+	Splits int     `json:"splits"`
+	Gain   float64 `json:"gain"`
+	// Thresholds count the splits at every threshold, in increasing order
+	Thresholds []ThresholdCount `json:"thresholds"`
+}
+
+// ThresholdCount is the number of splits at a threshold
+type ThresholdCount struct {
+	Threshold float32 `json:"threshold"`
+	Count     int     `json:"count"`
+}
+
+// Bin is a bin of a threshold histogram, counting the splits at thresholds from Min
+// to below Max, or up to Max for the last bin
+type Bin struct {
+	Min   float32 `json:"min"`
+	Max   float32 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// Anomaly is something suspicious about a tree
+type Anomaly struct {
+	Tree int `json:"tree"`
+	// Kind is "single_leaf" for a tree of a single leaf after the first round, which
+	// does not change predictions but its group's margin, or "non_finite" for a tree
+	// with a NaN or infinite leaf value or threshold
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Diagnose describe the trees of m and look for anomalies. Rounds are counted
+// assuming every round adds a tree per group.
+func (m *TreeModel) Diagnose() *Diagnostics {
+	d := &Diagnostics{
+		NumTrees:     len(m.Trees),
+		Trees:        make([]TreeStats, len(m.Trees)),
+		DepthCounts:  []int{},
+		Features:     map[int]*FeatureStats{},
+		DeadFeatures: []int{},
+		Anomalies:    []Anomaly{},
+	}
+	thresholds := map[int]map[float32]int{}
+	for i, tree := range m.Trees {
+		stats := TreeStats{Depth: tree.MaxDepth(), Features: []int{}}
+		used := map[int]bool{}
+		nonFinite := false
+		var visit func(id int)
+		visit = func(id int) {
+			n := &tree.Nodes[id]
+			if n.IsLeaf() {
+				stats.Leaves++
+				nonFinite = nonFinite || !finite(n.Leaf)
+				return
+			}
+			nonFinite = nonFinite || !finite(n.Threshold)
+			f := d.Features[n.Feature]
+			if f == nil {
+				f = &FeatureStats{}
+				d.Features[n.Feature] = f
+				thresholds[n.Feature] = map[float32]int{}
+			}
+			f.Splits++
+			f.Gain += n.Gain
+			thresholds[n.Feature][n.Threshold]++
+			if !used[n.Feature] {
+				used[n.Feature] = true
+				stats.Features = append(stats.Features, n.Feature)
+			}
+			visit(n.Yes)
+			visit(n.No)
+		}
+		visit(0)
+		sort.Ints(stats.Features)
+		d.Trees[i] = stats
+
+		for len(d.DepthCounts) <= stats.Depth {
+			d.DepthCounts = append(d.DepthCounts, 0)
+		}
+		d.DepthCounts[stats.Depth]++
+		if round := i / m.NumGroup; stats.Leaves == 1 && round > 0 {
+			d.Anomalies = append(d.Anomalies, Anomaly{
+				Tree:    i,
+				Kind:    "single_leaf",
+				Message: fmt.Sprintf("tree %d of round %d is a single leaf, later rounds may not be learning", i, round),
+			})
+		}
+		if nonFinite {
+			d.Anomalies = append(d.Anomalies, Anomaly{
+				Tree:    i,
+				Kind:    "non_finite",
+				Message: fmt.Sprintf("tree %d has a NaN or infinite leaf value or threshold", i),
+			})
+		}
+	}
+
+	for feature, counts := range thresholds {
+		f := d.Features[feature]
+		for threshold, count := range counts {
+			f.Thresholds = append(f.Thresholds, ThresholdCount{threshold, count})
+		}
+		sort.Slice(f.Thresholds, func(i, j int) bool { return f.Thresholds[i].Threshold < f.Thresholds[j].Threshold })
+	}
+	for feature := 0; feature < m.NumFeature; feature++ {
+		if d.Features[feature] == nil {
+			d.DeadFeatures = append(d.DeadFeatures, feature)
+		}
+	}
+	return d
+}
+
+// Histogram count the splits in bins of equal width between the smallest and largest
+// finite threshold, like get_split_value_histogram of the Python package
+func (f *FeatureStats) Histogram(bins int) []Bin {
+	min, max := float32(math.Inf(1)), float32(math.Inf(-1))
+	for _, t := range f.Thresholds {
+		if finite(t.Threshold) {
+			min = float32(math.Min(float64(min), float64(t.Threshold)))
+			max = float32(math.Max(float64(max), float64(t.Threshold)))
+		}
+	}
+	if bins < 1 || min > max {
+		return nil
+	}
+	if min == max {
+		bins = 1
+	}
+	width := (float64(max) - float64(min)) / float64(bins)
+	hist := make([]Bin, bins)
+	for i := range hist {
+		hist[i].Min = float32(float64(min) + float64(i)*width)
+		hist[i].Max = float32(float64(min) + float64(i+1)*width)
+	}
+	hist[bins-1].Max = max
+	for _, t := range f.Thresholds {
+		if !finite(t.Threshold) {
+			continue
+		}
+		i := bins - 1
+		if width > 0 {
+			i = int((float64(t.Threshold) - float64(min)) / width)
+		}
+		if i >= bins {
+			i = bins - 1
+		}
+		hist[i].Count += t.Count
+	}
+	return hist
+}
+
+func finite(v float32) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	trees, err := ParseTrees([]string{
+		`{"nodeid": 0, "split": 0, "split_condition": 2.5, "yes": 1, "no": 2, "missing": 2, "gain": 40, "cover": 100, "children": [
+			{"nodeid": 1, "split": 2, "split_condition": 1, "yes": 3, "no": 4, "missing": 3, "gain": 10, "cover": 60, "children": [
+				{"nodeid": 3, "leaf": 0.4}, {"nodeid": 4, "leaf": -0.2}]},
+			{"nodeid": 2, "leaf": 0.7}]}`,
+		`{"nodeid": 0, "split": 0, "split_condition": 2.5, "yes": 1, "no": 2, "missing": 1, "gain": 5, "cover": 100, "children": [
+			{"nodeid": 1, "leaf": -0.1}, {"nodeid": 2, "leaf": 0.1}]}`,
+		`{"nodeid": 0, "split": 0, "split_condition": 4, "yes": 1, "no": 2, "missing": 1, "gain": 1, "cover": 100, "children": [
+			{"nodeid": 1, "leaf": -0.1}, {"nodeid": 2, "leaf": 0.1}]}`,
+		`{"nodeid": 0, "leaf": 0.05}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trees[2].Nodes[2].Leaf = float32(math.Inf(1))
+	m := &TreeModel{Trees: trees, TreeGroups: []int{0, 0, 0, 0}, NumGroup: 1, NumFeature: 4}
+	d := m.Diagnose()
+
+	wantTrees := []TreeStats{{2, 3, []int{0, 2}}, {1, 2, []int{0}}, {1, 2, []int{0}}, {0, 1, []int{}}}
+	if !reflect.DeepEqual(d.Trees, wantTrees) {
+		t.Errorf("got trees %v, expected %v", d.Trees, wantTrees)
+	}
+	if want := []int{1, 2, 1}; !reflect.DeepEqual(d.DepthCounts, want) {
+		t.Errorf("got depth counts %v, expected %v", d.DepthCounts, want)
+	}
+	wantFeatures := map[int]*FeatureStats{
+		0: {Splits: 3, Gain: 46, Thresholds: []ThresholdCount{{2.5, 2}, {4, 1}}},
+		2: {Splits: 1, Gain: 10, Thresholds: []ThresholdCount{{1, 1}}},
+	}
+	if !reflect.DeepEqual(d.Features, wantFeatures) {
+		t.Errorf("got features %v, expected %v", d.Features, wantFeatures)
+	}
+	if want := []int{1, 3}; !reflect.DeepEqual(d.DeadFeatures, want) {
+		t.Errorf("got dead features %v, expected %v", d.DeadFeatures, want)
+	}
+	var kinds []string
+	for _, a := range d.Anomalies {
+		kinds = append(kinds, a.Kind)
+	}
+	if want := []string{"non_finite", "single_leaf"}; !reflect.DeepEqual(kinds, want) || d.Anomalies[1].Tree != 3 {
+		t.Errorf("got anomalies %v, expected %v", d.Anomalies, want)
+	}
+
+	// a single leaf in the first round of a multiclass model is no anomaly
+	m = &TreeModel{Trees: trees[2:], TreeGroups: []int{0, 1}, NumGroup: 2, NumFeature: 1}
+	if d := m.Diagnose(); len(d.Anomalies) != 1 || d.Anomalies[0].Kind != "non_finite" {
+		t.Errorf("got anomalies %v, expected only non_finite", d.Anomalies)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	f := &FeatureStats{Thresholds: []ThresholdCount{{0, 1}, {1, 2}, {2.5, 1}, {4, 3}}}
+	want := []Bin{{0, 2, 3}, {2, 4, 4}}
+	if got := f.Histogram(2); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, expected %v", got, want)
+	}
+	f = &FeatureStats{Thresholds: []ThresholdCount{{3, 2}}}
+	if got, want := f.Histogram(4), []Bin{{3, 3, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, expected %v", got, want)
+	}
+	if got := (&FeatureStats{}).Histogram(4); got != nil {
+		t.Errorf("got %v for no thresholds", got)
+	}
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// OutputTransform is how an objective turns the margins of a row into predictions
+type OutputTransform int
+
+const (
+	// TransformNone keeps the margin, for regression and ranking
+	TransformNone OutputTransform = iota
+	// TransformSigmoid gives the probability of the positive class
+	TransformSigmoid
+	// TransformExp gives the exponential of the margin
+	TransformExp
+	// TransformSoftmax gives the probability of every class
+	TransformSoftmax
+	// TransformArgmax gives the index of the class with the largest margin
+	TransformArgmax
+	// TransformHinge gives 1 for a positive margin and 0 otherwise
+	TransformHinge
+)
+
+// ObjectiveTransform get the output transform of objective, an error for an
+// objective it does not know
+func ObjectiveTransform(objective string) (OutputTransform, error) {
+	switch strings.TrimPrefix(objective, "gpu:") {
+	case "reg:linear", "reg:squarederror", "binary:logitraw", "rank:pairwise", "rank:ndcg", "rank:map":
+		return TransformNone, nil
+	case "binary:logistic", "reg:logistic":
+		return TransformSigmoid, nil
+	case "count:poisson", "reg:gamma", "reg:tweedie", "survival:cox":
+		return TransformExp, nil
+	case "multi:softprob":
+		return TransformSoftmax, nil
+	case "multi:softmax":
+		return TransformArgmax, nil
+	case "binary:hinge":
+		return TransformHinge, nil
+	}
+	return 0, fmt.Errorf("unknown objective %q", objective)
+}
+
+// Apply transform the margins of a row in place and return them. TransformArgmax
+// gives a single value, the index of the predicted class.
+func (t OutputTransform) Apply(margins []float32) []float32 {
+	switch t {
+	case TransformSigmoid:
+		for i, v := range margins {
+			margins[i] = sigmoid(v)
+		}
+	case TransformExp:
+		for i, v := range margins {
+			margins[i] = float32(math.Exp(float64(v)))
+		}
+	case TransformSoftmax:
+		softmax(margins)
+	case TransformArgmax:
+		best := 0
+		for i, v := range margins {
+			if v > margins[best] {
+				best = i
+			}
+		}
+		return []float32{float32(best)}
+	case TransformHinge:
+		for i, v := range margins {
+			if v > 0 {
+				margins[i] = 1
+			} else {
+				margins[i] = 0
+			}
+		}
+	}
+	return margins
+}
+
+// Transform apply the output transform of objective to the margins of a row in place
+// and return them, see ObjectiveTransform and OutputTransform.Apply
+func Transform(objective string, margins []float32) ([]float32, error) {
+	t, err := ObjectiveTransform(objective)
+	if err != nil {
+		return nil, err
+	}
+	return t.Apply(margins), nil
+}
+
+func sigmoid(v float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(v))))
+}
+
+func softmax(margins []float32) {
+	max := margins[0]
+	for _, v := range margins {
+		if v > max {
+			max = v
+		}
+	}
+	var sum float64
+	for i, v := range margins {
+		e := math.Exp(float64(v - max))
+		margins[i] = float32(e)
+		sum += e
+	}
+	for i := range margins {
+		margins[i] = float32(float64(margins[i]) / sum)
+	}
+}
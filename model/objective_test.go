@@ -0,0 +1,40 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	for _, c := range []struct {
+		objective string
+		margins   []float32
+		want      []float32
+	}{
+		{"reg:linear", []float32{-1.5}, []float32{-1.5}},
+		{"rank:pairwise", []float32{2}, []float32{2}},
+		{"binary:logistic", []float32{0}, []float32{0.5}},
+		{"gpu:reg:logistic", []float32{0}, []float32{0.5}},
+		{"count:poisson", []float32{0}, []float32{1}},
+		{"binary:hinge", []float32{-0.5}, []float32{0}},
+		{"binary:hinge", []float32{0}, []float32{0}},
+		{"binary:hinge", []float32{0.25}, []float32{1}},
+		{"multi:softprob", []float32{0, 0}, []float32{0.5, 0.5}},
+		{"multi:softmax", []float32{0.1, 0.7, 0.2}, []float32{1}},
+	} {
+		got, err := Transform(c.objective, append([]float32{}, c.margins...))
+		if err != nil {
+			t.Errorf("%s: %v", c.objective, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s of %v: got %v, expected %v", c.objective, c.margins, got, c.want)
+		}
+	}
+
+	for _, objective := range []string{"", "binary:unknown", "reg:unknown"} {
+		if _, err := ObjectiveTransform(objective); err == nil {
+			t.Errorf("expected an error for objective %q", objective)
+		}
+	}
+}
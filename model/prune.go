@@ -0,0 +1,38 @@
+package model
+
+// TotalGain get the sum of the gains of the splits of t, 0 for a single leaf
+func (t *Tree) TotalGain() float64 {
+	var gain float64
+	for i := range t.Nodes {
+		if !t.Nodes[i].IsLeaf() {
+			gain += t.Nodes[i].Gain
+		}
+	}
+	return gain
+}
+
+// Truncate get a model of the trees of the first numRounds rounds, which predicts
+// like m with an ntreeLimit of numRounds; 0 keeps every tree. The trees are shared
+// with m.
+func (m *TreeModel) Truncate(numRounds int) *TreeModel {
+	n := len(m.trees(numRounds))
+	return m.withTrees(func(i int) bool { return i < n })
+}
+
+// PruneTrees get a model without the trees whose splits gain less than minGain in
+// total, single leaves included. The trees are shared with m.
+func (m *TreeModel) PruneTrees(minGain float64) *TreeModel {
+	return m.withTrees(func(i int) bool { return m.Trees[i].TotalGain() >= minGain })
+}
+
+func (m *TreeModel) withTrees(keep func(i int) bool) *TreeModel {
+	pruned := *m
+	pruned.Trees, pruned.TreeGroups = nil, nil
+	for i, tree := range m.Trees {
+		if keep(i) {
+			pruned.Trees = append(pruned.Trees, tree)
+			pruned.TreeGroups = append(pruned.TreeGroups, m.TreeGroups[i])
+		}
+	}
+	return &pruned
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPruneTrees(t *testing.T) {
+	trees, err := ParseTrees([]string{
+		`{"nodeid": 0, "split": 0, "split_condition": 1, "yes": 1, "no": 2, "missing": 1, "gain": 8, "children": [
+			{"nodeid": 1, "leaf": -1}, {"nodeid": 2, "leaf": 1}]}`,
+		`{"nodeid": 0, "split": 1, "split_condition": 1, "yes": 1, "no": 2, "missing": 1, "gain": 2, "children": [
+			{"nodeid": 1, "leaf": -0.5}, {"nodeid": 2, "leaf": 0.5}]}`,
+		`{"nodeid": 0, "leaf": 0.1}`,
+		`{"nodeid": 0, "split": 0, "split_condition": 3, "yes": 1, "no": 2, "missing": 1, "gain": 3, "children": [
+			{"nodeid": 1, "split": 1, "split_condition": 0, "yes": 3, "no": 4, "missing": 3, "gain": 1, "children": [
+				{"nodeid": 3, "leaf": 0.2}, {"nodeid": 4, "leaf": 0.3}]},
+			{"nodeid": 2, "leaf": 0.4}]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &TreeModel{Trees: trees, TreeGroups: []int{0, 1, 0, 1}, NumGroup: 2, NumFeature: 2, BaseMargin: 0.5}
+
+	truncated := m.Truncate(1)
+	if !reflect.DeepEqual(truncated.TreeGroups, []int{0, 1}) || truncated.Trees[1] != trees[1] {
+		t.Errorf("truncated to groups %v", truncated.TreeGroups)
+	}
+	for _, row := range [][]float32{{0, 0}, {2, 2}, {4, -1}} {
+		if got, want := truncated.PredictMargin(row, 0), m.PredictMargin(row, 1); !reflect.DeepEqual(got, want) {
+			t.Errorf("row %v: truncated %v, limited %v", row, got, want)
+		}
+	}
+	if got := m.Truncate(0); len(got.Trees) != 4 {
+		t.Errorf("truncating to 0 rounds kept %d trees", len(got.Trees))
+	}
+
+	if got := trees[3].TotalGain(); got != 4 {
+		t.Errorf("got total gain %v, expected 4", got)
+	}
+	pruned := m.PruneTrees(3)
+	if !reflect.DeepEqual(pruned.TreeGroups, []int{0, 1}) || pruned.Trees[0] != trees[0] || pruned.Trees[1] != trees[3] {
+		t.Errorf("pruned to groups %v", pruned.TreeGroups)
+	}
+	if pruned.BaseMargin != m.BaseMargin || len(m.Trees) != 4 {
+		t.Error("pruning changed the model")
+	}
+}
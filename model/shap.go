@@ -0,0 +1,264 @@
+package model
+
+import "sort"
+
+// pathElement is an element of the path of features from the root in TreeSHAP
+type pathElement struct {
+	feature      int
+	zeroFraction float64
+	oneFraction  float64
+	weight       float64
+}
+
+// The TreeSHAP algorithm follows "Consistent Individualized Feature Attribution for
+// Tree Ensembles" (Lundberg et al.), as implemented by xgboost's pred_contribs.
+
+func extendPath(path []pathElement, depth int, zeroFraction float64, oneFraction float64, feature int) {
+	path[depth] = pathElement{feature: feature, zeroFraction: zeroFraction, oneFraction: oneFraction}
+	if depth == 0 {
+		path[depth].weight = 1
+	}
+	for i := depth - 1; i >= 0; i-- {
+		path[i+1].weight += oneFraction * path[i].weight * float64(i+1) / float64(depth+1)
+		path[i].weight = zeroFraction * path[i].weight * float64(depth-i) / float64(depth+1)
+	}
+}
+
+func unwindPath(path []pathElement, depth int, index int) {
+	oneFraction := path[index].oneFraction
+	zeroFraction := path[index].zeroFraction
+	nextOnePortion := path[depth].weight
+	for i := depth - 1; i >= 0; i-- {
+		if oneFraction != 0 {
+			tmp := path[i].weight
+			path[i].weight = nextOnePortion * float64(depth+1) / (float64(i+1) * oneFraction)
+			nextOnePortion = tmp - path[i].weight*zeroFraction*float64(depth-i)/float64(depth+1)
+		} else {
+			path[i].weight = path[i].weight * float64(depth+1) / (zeroFraction * float64(depth-i))
+		}
+	}
+	for i := index; i < depth; i++ {
+		path[i].feature = path[i+1].feature
+		path[i].zeroFraction = path[i+1].zeroFraction
+		path[i].oneFraction = path[i+1].oneFraction
+	}
+}
+
+// unwoundPathSum get the total weight of the path with element index unwound
+func unwoundPathSum(path []pathElement, depth int, index int) float64 {
+	oneFraction := path[index].oneFraction
+	zeroFraction := path[index].zeroFraction
+	nextOnePortion := path[depth].weight
+	var total float64
+	for i := depth - 1; i >= 0; i-- {
+		if oneFraction != 0 {
+			tmp := nextOnePortion * float64(depth+1) / (float64(i+1) * oneFraction)
+			total += tmp
+			nextOnePortion = path[i].weight - tmp*zeroFraction*float64(depth-i)/float64(depth+1)
+		} else if zeroFraction != 0 {
+			total += path[i].weight / zeroFraction / (float64(depth-i) / float64(depth+1))
+		}
+	}
+	return total
+}
+
+// meanValues get the expected value of every node, the cover weighted mean of its leaves
+func (t *Tree) meanValues() []float64 {
+	means := make([]float64, len(t.Nodes))
+	var fill func(id int) float64
+	fill = func(id int) float64 {
+		n := &t.Nodes[id]
+		if n.IsLeaf() {
+			means[id] = float64(n.Leaf)
+		} else {
+			means[id] = (fill(n.Yes)*t.Nodes[n.Yes].Cover + fill(n.No)*t.Nodes[n.No].Cover) / n.Cover
+		}
+		return means[id]
+	}
+	fill(0)
+	return means
+}
+
+// shap add the SHAP values of the tree for the row to phi, which has a column for
+// every feature and the bias last. A condition of 1 or -1 computes them with
+// conditionFeature fixed on or off, and leaves out the bias.
+func (t *Tree) shap(features []float32, phi []float64, condition int, conditionFeature int) {
+	if condition == 0 {
+		phi[len(phi)-1] += t.meanValues()[0]
+	}
+	maxDepth := t.MaxDepth() + 2
+	path := make([]pathElement, maxDepth*(maxDepth+1)/2)
+	t.treeShap(features, phi, 0, 0, path, 1, 1, -1, condition, conditionFeature, 1)
+}
+
+func (t *Tree) treeShap(features []float32, phi []float64, id int, depth int, parentPath []pathElement,
+	parentZeroFraction float64, parentOneFraction float64, parentFeature int,
+	condition int, conditionFeature int, conditionFraction float64) {
+	// stop if no weight comes down to this node
+	if conditionFraction == 0 {
+		return
+	}
+	path := parentPath[depth+1:]
+	copy(path, parentPath[:depth+1])
+	if condition == 0 || conditionFeature != parentFeature {
+		extendPath(path, depth, parentZeroFraction, parentOneFraction, parentFeature)
+	}
+
+	n := &t.Nodes[id]
+	if n.IsLeaf() {
+		for i := 1; i <= depth; i++ {
+			w := unwoundPathSum(path, depth, i)
+			el := &path[i]
+			phi[el.feature] += w * (el.oneFraction - el.zeroFraction) * float64(n.Leaf) * conditionFraction
+		}
+		return
+	}
+
+	// the hot child is the one the row follows
+	hot := n.next(features)
+	cold := n.Yes
+	if hot == n.Yes {
+		cold = n.No
+	}
+	hotZeroFraction := t.Nodes[hot].Cover / n.Cover
+	coldZeroFraction := t.Nodes[cold].Cover / n.Cover
+	incomingZeroFraction, incomingOneFraction := 1.0, 1.0
+
+	// undo an earlier split on the same feature, to redo it for this node
+	index := 0
+	for ; index <= depth; index++ {
+		if path[index].feature == n.Feature {
+			break
+		}
+	}
+	if index != depth+1 {
+		incomingZeroFraction = path[index].zeroFraction
+		incomingOneFraction = path[index].oneFraction
+		unwindPath(path, depth, index)
+		depth--
+	}
+
+	// divide the condition fraction among the children
+	hotConditionFraction, coldConditionFraction := conditionFraction, conditionFraction
+	if condition > 0 && n.Feature == conditionFeature {
+		coldConditionFraction = 0
+		depth--
+	} else if condition < 0 && n.Feature == conditionFeature {
+		hotConditionFraction *= hotZeroFraction
+		coldConditionFraction *= coldZeroFraction
+		depth--
+	}
+	t.treeShap(features, phi, hot, depth+1, path, hotZeroFraction*incomingZeroFraction, incomingOneFraction,
+		n.Feature, condition, conditionFeature, hotConditionFraction)
+	t.treeShap(features, phi, cold, depth+1, path, coldZeroFraction*incomingZeroFraction, 0,
+		n.Feature, condition, conditionFeature, coldConditionFraction)
+}
+
+// numColumns get the number of feature columns of SHAP values
+func (m *TreeModel) numColumns() int {
+	columns := m.NumFeature
+	for _, tree := range m.Trees {
+		for i := range tree.Nodes {
+			if tree.Nodes[i].Feature >= columns {
+				columns = tree.Nodes[i].Feature + 1
+			}
+		}
+	}
+	return columns
+}
+
+// contributions compute the SHAP values of every group with a condition on a feature
+func (m *TreeModel) contributions(features []float32, columns int, condition int, conditionFeature int) [][]float64 {
+	phi := make([][]float64, m.NumGroup)
+	for group := range phi {
+		phi[group] = make([]float64, columns+1)
+		if condition == 0 {
+			phi[group][columns] = float64(m.BaseMargin)
+		}
+	}
+	for i, tree := range m.Trees {
+		tree.shap(features, phi[m.TreeGroups[i]], condition, conditionFeature)
+	}
+	return phi
+}
+
+// SHAP compute the exact TreeSHAP values of the row, using the cover statistics of the
+// trees. For every group there is a value per feature and the bias last, and they add
+// up to the margin of the group, like the pred_contribs output of Booster.Predict.
+func (m *TreeModel) SHAP(features []float32) [][]float64 {
+	return m.contributions(features, m.numColumns(), 0, 0)
+}
+
+// SHAPInteractions compute the SHAP interaction values of the row, for every group a
+// (features+1)x(features+1) matrix with the bias last. The off diagonal entries split
+// the interaction of two features evenly, and every row adds up to the SHAP value of
+// its feature, like the pred_interactions output of Booster.Predict.
+func (m *TreeModel) SHAPInteractions(features []float32) [][][]float64 {
+	columns := m.numColumns()
+	diag := m.contributions(features, columns, 0, 0)
+	interactions := make([][][]float64, m.NumGroup)
+	for group := range interactions {
+		interactions[group] = make([][]float64, columns+1)
+	}
+	for i := 0; i <= columns; i++ {
+		off := m.contributions(features, columns, -1, i)
+		on := m.contributions(features, columns, 1, i)
+		for group := range interactions {
+			row := make([]float64, columns+1)
+			for k := range row {
+				if k != i {
+					row[k] = (on[group][k] - off[group][k]) / 2
+					row[i] -= row[k]
+				}
+			}
+			row[i] += diag[group][i]
+			interactions[group][i] = row
+		}
+	}
+	return interactions
+}
+
+// MeanAbsSHAP get the mean absolute SHAP value of every feature over rows, for every
+// group. It is a global importance of the features.
+func (m *TreeModel) MeanAbsSHAP(rows Matrix) [][]float64 {
+	columns := m.numColumns()
+	means := make([][]float64, m.NumGroup)
+	for group := range means {
+		means[group] = make([]float64, columns)
+	}
+	for _, row := range rows {
+		for group, phi := range m.SHAP(row) {
+			for j := 0; j < columns; j++ {
+				if phi[j] < 0 {
+					means[group][j] -= phi[j]
+				} else {
+					means[group][j] += phi[j]
+				}
+			}
+		}
+	}
+	if len(rows) > 0 {
+		for _, mean := range means {
+			for j := range mean {
+				mean[j] /= float64(len(rows))
+			}
+		}
+	}
+	return means
+}
+
+// TopFeatures get the indices of the n features with the largest values, such as those
+// of MeanAbsSHAP, largest first. n below 1 ranks every feature.
+func TopFeatures(values []float64, n int) []int {
+	indices := make([]int, len(values))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return values[indices[i]] > values[indices[j]]
+	})
+	if n > 0 && n < len(indices) {
+		indices = indices[:n]
+	}
+	return indices
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+// expectation get the expected prediction of the tree when only the features in known
+// are known, averaging over the others by cover
+func expectation(tree *Tree, id int, features []float32, known map[int]bool) float64 {
+	n := &tree.Nodes[id]
+	if n.IsLeaf() {
+		return float64(n.Leaf)
+	}
+	if known[n.Feature] {
+		return expectation(tree, n.next(features), features, known)
+	}
+	return (expectation(tree, n.Yes, features, known)*tree.Nodes[n.Yes].Cover +
+		expectation(tree, n.No, features, known)*tree.Nodes[n.No].Cover) / n.Cover
+}
+
+func factorial(n int) float64 {
+	f := 1.0
+	for i := 2; i <= n; i++ {
+		f *= float64(i)
+	}
+	return f
+}
+
+// subsets call f with every subset of features without the excluded ones
+func subsets(numFeature int, exclude map[int]bool, f func(set map[int]bool)) {
+	for mask := 0; mask < 1<<uint(numFeature); mask++ {
+		set := map[int]bool{}
+		skip := false
+		for j := 0; j < numFeature; j++ {
+			if mask&(1<<uint(j)) != 0 {
+				if exclude[j] {
+					skip = true
+				}
+				set[j] = true
+			}
+		}
+		if !skip {
+			f(set)
+		}
+	}
+}
+
+func with(set map[int]bool, features ...int) map[int]bool {
+	union := map[int]bool{}
+	for j := range set {
+		union[j] = true
+	}
+	for _, j := range features {
+		union[j] = true
+	}
+	return union
+}
+
+// bruteForceSHAP compute Shapley values from their definition
+func bruteForceSHAP(tree *Tree, features []float32, numFeature int) []float64 {
+	phi := make([]float64, numFeature+1)
+	phi[numFeature] = expectation(tree, 0, features, nil)
+	for i := 0; i < numFeature; i++ {
+		subsets(numFeature, map[int]bool{i: true}, func(set map[int]bool) {
+			weight := factorial(len(set)) * factorial(numFeature-len(set)-1) / factorial(numFeature)
+			phi[i] += weight * (expectation(tree, 0, features, with(set, i)) - expectation(tree, 0, features, set))
+		})
+	}
+	return phi
+}
+
+// bruteForceInteractions compute Shapley interaction values from their definition
+func bruteForceInteractions(tree *Tree, features []float32, numFeature int) [][]float64 {
+	phi := bruteForceSHAP(tree, features, numFeature)
+	interactions := make([][]float64, numFeature+1)
+	for i := range interactions {
+		interactions[i] = make([]float64, numFeature+1)
+	}
+	interactions[numFeature][numFeature] = phi[numFeature]
+	for i := 0; i < numFeature; i++ {
+		interactions[i][i] = phi[i]
+		for j := 0; j < numFeature; j++ {
+			if i == j {
+				continue
+			}
+			subsets(numFeature, map[int]bool{i: true, j: true}, func(set map[int]bool) {
+				weight := factorial(len(set)) * factorial(numFeature-len(set)-2) / (2 * factorial(numFeature-1))
+				delta := expectation(tree, 0, features, with(set, i, j)) - expectation(tree, 0, features, with(set, i)) -
+					expectation(tree, 0, features, with(set, j)) + expectation(tree, 0, features, set)
+				interactions[i][j] += weight * delta
+			})
+			interactions[i][i] -= interactions[i][j]
+		}
+	}
+	return interactions
+}
+
+func TestSHAP(t *testing.T) {
+	tree, err := ParseTree(testDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &TreeModel{Trees: []*Tree{tree}, TreeGroups: []int{0}, NumGroup: 1, NumFeature: 4}
+	nan := float32(math.NaN())
+	rows := Matrix{{1, 0, 0, 9}, {1, 0, 1, 9}, {2, 5, 1, 9}, {nan, 0, nan, 9}, {3, 1, 0, 9}, {3, 4, 2, 9}}
+	for _, row := range rows {
+		phi := m.SHAP(row)[0]
+		want := bruteForceSHAP(tree, row, 4)
+		var sum float64
+		for j := range phi {
+			if math.Abs(phi[j]-want[j]) > 1e-9 {
+				t.Errorf("row %v feature %d: SHAP %v, brute force %v", row, j, phi[j], want[j])
+			}
+			sum += phi[j]
+		}
+		if math.Abs(sum-float64(tree.Predict(row))) > 1e-9 {
+			t.Errorf("row %v: SHAP values sum to %v, prediction %v", row, sum, tree.Predict(row))
+		}
+
+		interactions := m.SHAPInteractions(row)[0]
+		wantInteractions := bruteForceInteractions(tree, row, 4)
+		for i := range interactions {
+			var rowSum float64
+			for j := range interactions[i] {
+				if math.Abs(interactions[i][j]-wantInteractions[i][j]) > 1e-9 {
+					t.Errorf("row %v: interaction %d,%d is %v, brute force %v", row, i, j, interactions[i][j], wantInteractions[i][j])
+				}
+				rowSum += interactions[i][j]
+			}
+			if math.Abs(rowSum-phi[i]) > 1e-9 {
+				t.Errorf("row %v: interactions of %d sum to %v, SHAP %v", row, i, rowSum, phi[i])
+			}
+		}
+	}
+
+	means := m.MeanAbsSHAP(rows)[0]
+	if means[3] != 0 || means[0] == 0 {
+		t.Errorf("unexpected mean |SHAP| %v", means)
+	}
+	if top := TopFeatures(means, 2); len(top) != 2 || means[top[0]] < means[top[1]] || top[0] == 3 {
+		t.Errorf("unexpected top features %v of %v", top, means)
+	}
+}
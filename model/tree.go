@@ -0,0 +1,181 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of a Tree
+type Node struct {
+	ID    int
+	Depth int
+	// Feature is the index of the split feature, -1 for leaves and unused ids
+	Feature int
+	// Threshold send rows with a feature value below it to Yes, the others to No
+	Threshold float32
+	Yes       int
+	No        int
+	Missing   int
+	Leaf      float32
+	Gain      float64
+	Cover     float64
+}
+
+// IsLeaf report whether n is a leaf
+func (n *Node) IsLeaf() bool {
+	return n.Feature < 0
+}
+
+// Tree is a regression tree, with Nodes indexed by node id and the root at 0
+type Tree struct {
+	Nodes []Node
+}
+
+// jsonNode is a node of a tree dumped by DumpModelEx in json format
+type jsonNode struct {
+	NodeID         int         `json:"nodeid"`
+	Depth          int         `json:"depth"`
+	Split          interface{} `json:"split"`
+	SplitCondition float64     `json:"split_condition"`
+	Yes            int         `json:"yes"`
+	No             int         `json:"no"`
+	Missing        int         `json:"missing"`
+	Gain           float64     `json:"gain"`
+	Cover          float64     `json:"cover"`
+	Leaf           *float64    `json:"leaf"`
+	Children       []jsonNode  `json:"children"`
+}
+
+// ParseTree parse a tree dumped by DumpModelEx in json format without a feature map,
+// where splits name features by index. Gain and Cover are only set for dumps with stats.
+func ParseTree(dump string) (*Tree, error) {
+	var root jsonNode
+	if err := json.Unmarshal([]byte(dump), &root); err != nil {
+		return nil, fmt.Errorf("parse tree: %v", err)
+	}
+	tree := &Tree{}
+	if err := tree.add(&root, 0); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// ParseTrees parse every tree of a dump
+func ParseTrees(dumps []string) ([]*Tree, error) {
+	trees := make([]*Tree, len(dumps))
+	for i, dump := range dumps {
+		tree, err := ParseTree(dump)
+		if err != nil {
+			return nil, fmt.Errorf("tree %d: %v", i, err)
+		}
+		trees[i] = tree
+	}
+	return trees, nil
+}
+
+func (t *Tree) add(n *jsonNode, depth int) error {
+	if n.NodeID < 0 {
+		return fmt.Errorf("negative node id %d", n.NodeID)
+	}
+	for len(t.Nodes) <= n.NodeID {
+		t.Nodes = append(t.Nodes, Node{ID: len(t.Nodes), Feature: -1})
+	}
+	node := Node{ID: n.NodeID, Depth: depth, Feature: -1, Gain: n.Gain, Cover: n.Cover}
+	if n.Leaf != nil {
+		node.Leaf = float32(*n.Leaf)
+		t.Nodes[n.NodeID] = node
+		return nil
+	}
+
+	feature, err := splitFeature(n.Split)
+	if err != nil {
+		return fmt.Errorf("node %d: %v", n.NodeID, err)
+	}
+	if len(n.Children) != 2 {
+		return fmt.Errorf("node %d has %d children", n.NodeID, len(n.Children))
+	}
+	node.Feature = feature
+	node.Threshold = float32(n.SplitCondition)
+	node.Yes, node.No, node.Missing = n.Yes, n.No, n.Missing
+	t.Nodes[n.NodeID] = node
+	for i := range n.Children {
+		child := n.Children[i].NodeID
+		if child != n.Yes && child != n.No {
+			return fmt.Errorf("node %d has unexpected child %d", n.NodeID, child)
+		}
+		if err := t.add(&n.Children[i], depth+1); err != nil {
+			return err
+		}
+	}
+	if n.Missing != n.Yes && n.Missing != n.No {
+		return fmt.Errorf("node %d sends missing values to %d, not a child", n.NodeID, n.Missing)
+	}
+	return nil
+}
+
+// splitFeature get the feature index of a dumped split, a number or "f<index>"
+func splitFeature(split interface{}) (int, error) {
+	switch s := split.(type) {
+	case float64:
+		if s < 0 || s != math.Trunc(s) {
+			return 0, fmt.Errorf("invalid split feature %v", s)
+		}
+		return int(s), nil
+	case string:
+		feature, err := strconv.Atoi(strings.TrimPrefix(s, "f"))
+		if err != nil || feature < 0 {
+			return 0, fmt.Errorf("split on %q, dump the model without a feature map", s)
+		}
+		return feature, nil
+	}
+	return 0, fmt.Errorf("invalid split %v", split)
+}
+
+// next get the child the row goes to from node n, NaN or absent features are missing
+func (n *Node) next(features []float32) int {
+	if n.Feature >= len(features) {
+		return n.Missing
+	}
+	v := features[n.Feature]
+	switch {
+	case v != v:
+		return n.Missing
+	case v < n.Threshold:
+		return n.Yes
+	}
+	return n.No
+}
+
+// LeafIndex get the id of the leaf the row ends in
+func (t *Tree) LeafIndex(features []float32) int {
+	id := 0
+	for !t.Nodes[id].IsLeaf() {
+		id = t.Nodes[id].next(features)
+	}
+	return id
+}
+
+// Predict get the leaf value of the row
+func (t *Tree) Predict(features []float32) float32 {
+	return t.Nodes[t.LeafIndex(features)].Leaf
+}
+
+// MaxDepth get the depth of the deepest leaf, 0 for a single leaf
+func (t *Tree) MaxDepth() int {
+	var depth func(id int) int
+	depth = func(id int) int {
+		n := &t.Nodes[id]
+		if n.IsLeaf() {
+			return 0
+		}
+		yes, no := depth(n.Yes), depth(n.No)
+		if yes > no {
+			return yes + 1
+		}
+		return no + 1
+	}
+	return depth(0)
+}
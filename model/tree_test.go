@@ -0,0 +1,99 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+// testDump is a json dump with stats of a tree splitting twice on f0 and once on f2
+const testDump = `{ "nodeid": 0, "depth": 0, "split": 0, "split_condition": 2.5, "yes": 1, "no": 2, "missing": 1, "gain": 40, "cover": 100, "children": [
+    { "nodeid": 1, "depth": 1, "split": 2, "split_condition": 0.5, "yes": 3, "no": 4, "missing": 4, "gain": 10, "cover": 60, "children": [
+      { "nodeid": 3, "leaf": 0.5, "cover": 20 },
+      { "nodeid": 4, "depth": 2, "split": "f0", "split_condition": 1.5, "yes": 5, "no": 6, "missing": 5, "gain": 5, "cover": 40, "children": [
+        { "nodeid": 5, "leaf": -0.25, "cover": 30 },
+        { "nodeid": 6, "leaf": 1, "cover": 10 }
+      ]}
+    ]},
+    { "nodeid": 2, "depth": 1, "split": 1, "split_condition": 3, "yes": 7, "no": 8, "missing": 8, "gain": 8, "cover": 40, "children": [
+      { "nodeid": 7, "leaf": -1, "cover": 25 },
+      { "nodeid": 8, "leaf": 2, "cover": 15 }
+    ]}
+  ]}`
+
+func TestParseTree(t *testing.T) {
+	tree, err := ParseTree(testDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tree.Nodes) != 9 || tree.MaxDepth() != 3 {
+		t.Fatalf("parsed %d nodes of depth %d", len(tree.Nodes), tree.MaxDepth())
+	}
+	if n := tree.Nodes[4]; n.Feature != 0 || n.Threshold != 1.5 || n.Yes != 5 || n.No != 6 || n.Missing != 5 || n.Depth != 2 || n.Cover != 40 {
+		t.Errorf("unexpected node %+v", n)
+	}
+	nan := float32(math.NaN())
+	for _, c := range []struct {
+		features []float32
+		leaf     int
+	}{
+		{[]float32{1, 0, 0}, 3},
+		{[]float32{1, 0, 1}, 5},
+		{[]float32{2, 0, 1}, 6},
+		{[]float32{nan, 0, nan}, 5},
+		{[]float32{3, 1, 0}, 7},
+		{[]float32{3, 4}, 8},
+		{nil, 5},
+	} {
+		if leaf := tree.LeafIndex(c.features); leaf != c.leaf {
+			t.Errorf("%v ends in leaf %d, expected %d", c.features, leaf, c.leaf)
+		}
+	}
+	if v := tree.Predict([]float32{3, 4, 0}); v != 2 {
+		t.Errorf("predicted %v, expected 2", v)
+	}
+
+	for _, bad := range []string{
+		`{"nodeid": 0, "split": "age", "split_condition": 1, "yes": 1, "no": 2, "missing": 1, "children": [{"nodeid": 1, "leaf": 0}, {"nodeid": 2, "leaf": 1}]}`,
+		`{"nodeid": 0, "split": 0, "split_condition": 1, "yes": 1, "no": 2, "missing": 1, "children": [{"nodeid": 1, "leaf": 0}]}`,
+		`{"nodeid": 0, "split": 0, "split_condition": 1, "yes": 1, "no": 2, "missing": 3, "children": [{"nodeid": 1, "leaf": 0}, {"nodeid": 2, "leaf": 1}]}`,
+		`not json`,
+	} {
+		if _, err := ParseTree(bad); err == nil {
+			t.Errorf("expected an error parsing %s", bad)
+		}
+	}
+}
+
+func TestTreeModelPredict(t *testing.T) {
+	tree, err := ParseTree(testDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &TreeModel{Trees: []*Tree{tree, tree, tree}, TreeGroups: []int{0, 1, 0}, NumGroup: 2, NumFeature: 3, BaseMargin: 0.5, Objective: "multi:softprob"}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	row := []float32{3, 4, 0}
+	if margins := m.PredictMargin(row, 0); margins[0] != 4.5 || margins[1] != 2.5 {
+		t.Errorf("unexpected margins %v", margins)
+	}
+	if margins := m.PredictMargin(row, 1); margins[0] != 2.5 || margins[1] != 2.5 {
+		t.Errorf("unexpected margins of the first round %v", margins)
+	}
+	probs, err := m.Predict(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(float64(probs[0]+probs[1])-1) > 1e-6 || math.Abs(float64(probs[0])-1/(1+math.Exp(-2))) > 1e-6 {
+		t.Errorf("unexpected probabilities %v", probs)
+	}
+	m.Objective = "multi:unknown"
+	if _, err := m.Predict(row); err == nil {
+		t.Error("expected an error for an unknown objective")
+	}
+
+	m.TreeGroups = []int{0, 2, 0}
+	if err := m.Validate(); err == nil {
+		t.Error("expected an error for a group out of range")
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import "fmt"
+
+// TreeModel is a tree booster parsed from its dump, to predict and explain rows
+// without libxgboost
+type TreeModel struct {
+	Trees []*Tree
+	// TreeGroups is the output group of every tree, the class of multiclass models
+	TreeGroups []int
+	// NumGroup is the number of outputs, num_class of multiclass models and 1 otherwise
+	NumGroup   int
+	NumFeature int
+	// BaseMargin is the margin every prediction starts from, base_score after the
+	// objective's inverse transform
+	BaseMargin float32
+	Objective  string
+}
+
+// Validate check that the trees and their groups agree
+func (m *TreeModel) Validate() error {
+	if m.NumGroup < 1 {
+		return fmt.Errorf("invalid number of groups %d", m.NumGroup)
+	}
+	if len(m.TreeGroups) != len(m.Trees) {
+		return fmt.Errorf("%d tree groups for %d trees", len(m.TreeGroups), len(m.Trees))
+	}
+	for i, group := range m.TreeGroups {
+		if group < 0 || group >= m.NumGroup {
+			return fmt.Errorf("tree %d in group %d of %d", i, group, m.NumGroup)
+		}
+	}
+	return nil
+}
+
+// PredictMargin get the margin of every group for the row. Like the ntreeLimit of
+// Booster.Predict, a limit other than 0 uses only the first ntreeLimit trees of every
+// group.
+func (m *TreeModel) PredictMargin(features []float32, ntreeLimit int) []float32 {
+	margins := make([]float32, m.NumGroup)
+	for i := range margins {
+		margins[i] = m.BaseMargin
+	}
+	for i, tree := range m.trees(ntreeLimit) {
+		margins[m.TreeGroups[i]] += tree.Predict(features)
+	}
+	return margins
+}
+
+// trees get the trees used with ntreeLimit
+func (m *TreeModel) trees(ntreeLimit int) []*Tree {
+	if ntreeLimit > 0 && ntreeLimit*m.NumGroup < len(m.Trees) {
+		return m.Trees[:ntreeLimit*m.NumGroup]
+	}
+	return m.Trees
+}
+
+// Predict get the prediction of the row, the margin transformed by the objective
+func (m *TreeModel) Predict(features []float32) ([]float32, error) {
+	return Transform(m.Objective, m.PredictMargin(features, 0))
+}
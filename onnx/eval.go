@@ -0,0 +1,444 @@
+package onnx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// attribute is a decoded node attribute
+type attribute struct {
+	f       float32
+	i       int64
+	s       string
+	floats  []float32
+	ints    []int64
+	strings []string
+}
+
+// graphNode is a decoded graph node
+type graphNode struct {
+	opType     string
+	inputs     []string
+	outputs    []string
+	attributes map[string]*attribute
+}
+
+// Evaluator run ONNX graphs made of tree ensembles and element wise Exp, such as those
+// written by Export, on single rows
+type Evaluator struct {
+	input   string
+	outputs []string
+	steps   []step
+}
+
+// step is a node of the graph, it reads its inputs from values and sets its outputs
+type step func(values map[string][]float32) error
+
+// NewEvaluator decode a serialized ONNX ModelProto
+func NewEvaluator(data []byte) (*Evaluator, error) {
+	fields, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var graph []byte
+	for _, f := range fields {
+		if f.number == 7 && f.wire == wireBytes {
+			graph = f.data
+		}
+	}
+	if graph == nil {
+		return nil, errors.New("onnx: model has no graph")
+	}
+	if fields, err = decode(graph); err != nil {
+		return nil, err
+	}
+
+	e := &Evaluator{}
+	for _, f := range fields {
+		switch f.number {
+		case 1:
+			node, err := decodeNode(f.data)
+			if err != nil {
+				return nil, err
+			}
+			s, err := newStep(node)
+			if err != nil {
+				return nil, err
+			}
+			e.steps = append(e.steps, s)
+		case 11, 12:
+			name, err := valueInfoName(f.data)
+			if err != nil {
+				return nil, err
+			}
+			if f.number == 11 {
+				if e.input != "" {
+					return nil, errors.New("onnx: graph has more than one input")
+				}
+				e.input = name
+			} else {
+				e.outputs = append(e.outputs, name)
+			}
+		}
+	}
+	if e.input == "" || len(e.outputs) == 0 {
+		return nil, errors.New("onnx: graph has no input or output")
+	}
+	return e, nil
+}
+
+func valueInfoName(data []byte) (string, error) {
+	fields, err := decode(data)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range fields {
+		if f.number == 1 {
+			return string(f.data), nil
+		}
+	}
+	return "", errors.New("onnx: value without a name")
+}
+
+func decodeNode(data []byte) (*graphNode, error) {
+	fields, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	node := &graphNode{attributes: map[string]*attribute{}}
+	for _, f := range fields {
+		switch f.number {
+		case 1:
+			node.inputs = append(node.inputs, string(f.data))
+		case 2:
+			node.outputs = append(node.outputs, string(f.data))
+		case 4:
+			node.opType = string(f.data)
+		case 5:
+			name, a, err := decodeAttribute(f.data)
+			if err != nil {
+				return nil, err
+			}
+			node.attributes[name] = a
+		}
+	}
+	return node, nil
+}
+
+func decodeAttribute(data []byte) (string, *attribute, error) {
+	fields, err := decode(data)
+	if err != nil {
+		return "", nil, err
+	}
+	var name string
+	a := &attribute{}
+	for _, f := range fields {
+		switch f.number {
+		case 1:
+			name = string(f.data)
+		case 2:
+			a.f = math.Float32frombits(uint32(f.value))
+		case 3:
+			a.i = int64(f.value)
+		case 4:
+			a.s = string(f.data)
+		case 7:
+			a.floats, err = appendFloats(a.floats, f)
+		case 8:
+			a.ints, err = appendInts(a.ints, f)
+		case 9:
+			a.strings = append(a.strings, string(f.data))
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	return name, a, nil
+}
+
+func newStep(node *graphNode) (step, error) {
+	if len(node.inputs) != 1 {
+		return nil, fmt.Errorf("onnx: %s node with %d inputs", node.opType, len(node.inputs))
+	}
+	input := node.inputs[0]
+	switch node.opType {
+	case "Exp":
+		if len(node.outputs) != 1 {
+			return nil, fmt.Errorf("onnx: Exp node with %d outputs", len(node.outputs))
+		}
+		output := node.outputs[0]
+		return func(values map[string][]float32) error {
+			in, ok := values[input]
+			if !ok {
+				return fmt.Errorf("onnx: no value %s", input)
+			}
+			out := make([]float32, len(in))
+			for i, v := range in {
+				out[i] = float32(math.Exp(float64(v)))
+			}
+			values[output] = out
+			return nil
+		}, nil
+	case "TreeEnsembleRegressor", "TreeEnsembleClassifier":
+		ensemble, err := newEnsemble(node)
+		if err != nil {
+			return nil, err
+		}
+		return func(values map[string][]float32) error {
+			in, ok := values[input]
+			if !ok {
+				return fmt.Errorf("onnx: no value %s", input)
+			}
+			return ensemble.run(in, node.outputs, values)
+		}, nil
+	}
+	return nil, fmt.Errorf("onnx: unsupported operator %s", node.opType)
+}
+
+// Run evaluate the graph on a row, returning every graph output by name. Labels of
+// classifiers are returned as float32.
+func (e *Evaluator) Run(features []float32) (map[string][]float32, error) {
+	values := map[string][]float32{e.input: features}
+	for _, s := range e.steps {
+		if err := s(values); err != nil {
+			return nil, err
+		}
+	}
+	outputs := map[string][]float32{}
+	for _, name := range e.outputs {
+		v, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("onnx: graph output %s is not computed", name)
+		}
+		outputs[name] = v
+	}
+	return outputs, nil
+}
+
+// ensembleTreeNode is a decoded node of a tree ensemble
+type ensembleTreeNode struct {
+	mode        string
+	feature     int
+	value       float32
+	yes         int
+	no          int
+	missingTrue bool
+	targets     []int
+	weights     []float32
+}
+
+// ensemble evaluate a TreeEnsembleRegressor or TreeEnsembleClassifier
+type ensemble struct {
+	classifier bool
+	// binary is set for classifiers of two classes with every weight on class 0
+	binary    bool
+	targets   int
+	base      []float32
+	transform string
+	nodes     []ensembleTreeNode
+	roots     []int
+	labels    []int64
+}
+
+func newEnsemble(node *graphNode) (*ensemble, error) {
+	attr := func(name string) *attribute {
+		if a, ok := node.attributes[name]; ok {
+			return a
+		}
+		return &attribute{}
+	}
+	e := &ensemble{classifier: node.opType == "TreeEnsembleClassifier", transform: attr("post_transform").s, base: attr("base_values").floats}
+	if e.transform == "" {
+		e.transform = "NONE"
+	}
+	if e.transform != "NONE" && e.transform != "LOGISTIC" && e.transform != "SOFTMAX" {
+		return nil, fmt.Errorf("onnx: unsupported post transform %s", e.transform)
+	}
+	if f := attr("aggregate_function").s; f != "" && f != "SUM" {
+		return nil, fmt.Errorf("onnx: unsupported aggregate function %s", f)
+	}
+	prefix := "target_"
+	if e.classifier {
+		prefix = "class_"
+		e.labels = attr("classlabels_int64s").ints
+		e.targets = len(e.labels)
+		if len(node.outputs) != 2 {
+			return nil, errors.New("onnx: classifier without label and probabilities outputs")
+		}
+	} else {
+		e.targets = int(attr("n_targets").i)
+		if len(node.outputs) != 1 {
+			return nil, errors.New("onnx: regressor without one output")
+		}
+	}
+	if e.targets < 1 {
+		return nil, errors.New("onnx: tree ensemble without targets")
+	}
+
+	treeIDs, nodeIDs := attr("nodes_treeids").ints, attr("nodes_nodeids").ints
+	modes, features, values := attr("nodes_modes").strings, attr("nodes_featureids").ints, attr("nodes_values").floats
+	trueIDs, falseIDs, missing := attr("nodes_truenodeids").ints, attr("nodes_falsenodeids").ints, attr("nodes_missing_value_tracks_true").ints
+	n := len(treeIDs)
+	if len(nodeIDs) != n || len(modes) != n || len(features) != n || len(values) != n || len(trueIDs) != n || len(falseIDs) != n {
+		return nil, errors.New("onnx: node attributes of different lengths")
+	}
+	type key struct{ tree, node int64 }
+	index := map[key]int{}
+	e.nodes = make([]ensembleTreeNode, n)
+	for i := range e.nodes {
+		index[key{treeIDs[i], nodeIDs[i]}] = i
+		e.nodes[i] = ensembleTreeNode{mode: modes[i], feature: int(features[i]), value: values[i]}
+		if i < len(missing) {
+			e.nodes[i].missingTrue = missing[i] != 0
+		}
+	}
+	isChild := make([]bool, n)
+	for i := range e.nodes {
+		if e.nodes[i].mode == "LEAF" {
+			continue
+		}
+		yes, okYes := index[key{treeIDs[i], trueIDs[i]}]
+		no, okNo := index[key{treeIDs[i], falseIDs[i]}]
+		if !okYes || !okNo {
+			return nil, fmt.Errorf("onnx: node %d of tree %d has a missing child", nodeIDs[i], treeIDs[i])
+		}
+		e.nodes[i].yes, e.nodes[i].no = yes, no
+		isChild[yes], isChild[no] = true, true
+	}
+	for i := range e.nodes {
+		if !isChild[i] {
+			e.roots = append(e.roots, i)
+		}
+	}
+
+	targetTrees, targetNodes := attr(prefix+"treeids").ints, attr(prefix+"nodeids").ints
+	targetIDs, weights := attr(prefix+"ids").ints, attr(prefix+"weights").floats
+	if len(targetNodes) != len(targetTrees) || len(targetIDs) != len(targetTrees) || len(weights) != len(targetTrees) {
+		return nil, errors.New("onnx: target attributes of different lengths")
+	}
+	for i := range targetTrees {
+		leaf, ok := index[key{targetTrees[i], targetNodes[i]}]
+		if !ok || targetIDs[i] < 0 || int(targetIDs[i]) >= e.targets {
+			return nil, fmt.Errorf("onnx: invalid target of node %d of tree %d", targetNodes[i], targetTrees[i])
+		}
+		e.nodes[leaf].targets = append(e.nodes[leaf].targets, int(targetIDs[i]))
+		e.nodes[leaf].weights = append(e.nodes[leaf].weights, weights[i])
+	}
+	e.binary = e.classifier && e.targets == 2
+	for _, target := range targetIDs {
+		e.binary = e.binary && target == 0
+	}
+	return e, nil
+}
+
+// follow get the child a row goes to from a branch node
+func (n *ensembleTreeNode) follow(features []float32) (int, error) {
+	if n.feature < 0 || n.feature >= len(features) {
+		return 0, fmt.Errorf("onnx: feature %d out of range", n.feature)
+	}
+	x := features[n.feature]
+	if x != x {
+		if n.missingTrue {
+			return n.yes, nil
+		}
+		return n.no, nil
+	}
+	var branch bool
+	switch n.mode {
+	case "BRANCH_LT":
+		branch = x < n.value
+	case "BRANCH_LEQ":
+		branch = x <= n.value
+	case "BRANCH_GT":
+		branch = x > n.value
+	case "BRANCH_GTE":
+		branch = x >= n.value
+	case "BRANCH_EQ":
+		branch = x == n.value
+	case "BRANCH_NEQ":
+		branch = x != n.value
+	default:
+		return 0, fmt.Errorf("onnx: unsupported node mode %s", n.mode)
+	}
+	if branch {
+		return n.yes, nil
+	}
+	return n.no, nil
+}
+
+func (e *ensemble) run(features []float32, outputs []string, values map[string][]float32) error {
+	scores := make([]float32, e.targets)
+	for _, root := range e.roots {
+		id := root
+		for e.nodes[id].mode != "LEAF" {
+			next, err := e.nodes[id].follow(features)
+			if err != nil {
+				return err
+			}
+			id = next
+		}
+		for i, target := range e.nodes[id].targets {
+			scores[target] += e.nodes[id].weights[i]
+		}
+	}
+	for i := range scores {
+		if i < len(e.base) {
+			scores[i] += e.base[i]
+		}
+	}
+
+	// the single score of a binary classifier is that of the positive class
+	if e.binary {
+		p := scores[0]
+		if e.transform == "LOGISTIC" {
+			p = sigmoid(p)
+		}
+		scores = []float32{1 - p, p}
+	} else {
+		switch e.transform {
+		case "LOGISTIC":
+			for i, v := range scores {
+				scores[i] = sigmoid(v)
+			}
+		case "SOFTMAX":
+			softmax(scores)
+		}
+	}
+
+	if !e.classifier {
+		values[outputs[0]] = scores
+		return nil
+	}
+	best := 0
+	for i, v := range scores {
+		if v > scores[best] {
+			best = i
+		}
+	}
+	values[outputs[0]] = []float32{float32(e.labels[best])}
+	values[outputs[1]] = scores
+	return nil
+}
+
+func sigmoid(v float32) float32 {
+	return float32(1 / (1 + math.Exp(-float64(v))))
+}
+
+func softmax(scores []float32) {
+	max := scores[0]
+	for _, v := range scores {
+		if v > max {
+			max = v
+		}
+	}
+	var sum float64
+	for i, v := range scores {
+		e := math.Exp(float64(v - max))
+		scores[i] = float32(e)
+		sum += e
+	}
+	for i := range scores {
+		scores[i] = float32(float64(scores[i]) / sum)
+	}
+}
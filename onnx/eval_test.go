@@ -0,0 +1,104 @@
+package onnx
+
+import (
+	"math"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testmodel"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+func closeTo(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+// checkExport compare the outputs of the exported graph with the predictions of m
+func checkExport(t *testing.T, m *model.TreeModel, rows model.Matrix, predict func(i int) []float32) {
+	data, err := Export(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEvaluator(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		outputs, err := e.Run(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := predict(i)
+		switch m.Objective {
+		case "binary:logistic":
+			probs := outputs[ProbabilitiesName]
+			if len(probs) != 2 || !closeTo(probs[1], want[0]) || !closeTo(probs[0]+probs[1], 1) {
+				t.Errorf("row %d: probabilities %v, expected %v", i, probs, want)
+			}
+			if label := outputs[LabelName][0]; (label == 1) != (want[0] > 0.5) {
+				t.Errorf("row %d: label %v for probability %v", i, label, want[0])
+			}
+		case "multi:softmax":
+			if label := outputs[LabelName]; label[0] != want[0] {
+				t.Errorf("row %d: label %v, expected %v", i, label, want)
+			}
+		case "multi:softprob":
+			probs := outputs[ProbabilitiesName]
+			for j := range want {
+				if !closeTo(probs[j], want[j]) {
+					t.Errorf("row %d: probabilities %v, expected %v", i, probs, want)
+				}
+			}
+		default:
+			values := outputs[OutputName]
+			for j := range want {
+				if !closeTo(values[j], want[j]) {
+					t.Errorf("row %d: output %v, expected %v", i, values, want)
+				}
+			}
+		}
+	}
+}
+
+func TestExportEvaluate(t *testing.T) {
+	for _, c := range []struct {
+		objective string
+		numGroup  int
+	}{
+		{"reg:linear", 1},
+		{"reg:logistic", 1},
+		{"count:poisson", 1},
+		{"binary:logistic", 1},
+		{"multi:softprob", 3},
+		{"multi:softmax", 3},
+	} {
+		t.Run(c.objective, func(t *testing.T) {
+			m := testmodel.Model(t, c.objective, c.numGroup)
+			checkExport(t, m, testmodel.Rows, func(i int) []float32 {
+				pred, err := m.Predict(testmodel.Rows[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				return pred
+			})
+		})
+	}
+}
+
+func TestExportObjectiveErrors(t *testing.T) {
+	for _, objective := range []string{"binary:hinge", "reg:unknown"} {
+		if _, err := Export(testmodel.Model(t, objective, 1)); err == nil {
+			t.Errorf("expected an error exporting a %s model", objective)
+		}
+	}
+}
+
+func TestEvaluatorErrors(t *testing.T) {
+	if _, err := NewEvaluator([]byte{0x0a, 0x05, 0x01}); err == nil {
+		t.Error("expected an error for a truncated model")
+	}
+	var empty message
+	empty.int(1, irVersion)
+	if _, err := NewEvaluator(empty); err == nil {
+		t.Error("expected an error for a model without a graph")
+	}
+}
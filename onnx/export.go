@@ -0,0 +1,276 @@
+// Package onnx exports tree models to ONNX-ML, as a graph with a TreeEnsembleRegressor
+// or TreeEnsembleClassifier node, and evaluates such graphs in pure Go. The protobuf
+// encoding is written by hand, so no ONNX or protobuf tooling is needed.
+//
+// The graph takes a float tensor [N, num_feature] named "input", with NaN for missing
+// values. Classifiers (binary:logistic and multi:*) output "label", an int64 tensor
+// [N], and "probabilities", a float tensor [N, classes]. Other objectives are
+// exported as regressors with a float tensor "variable" [N, groups], the prediction
+// of Booster.Predict.
+package onnx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+const (
+	irVersion      = 7
+	opsetVersion   = 13
+	mlOpsetVersion = 1
+	mlDomain       = "ai.onnx.ml"
+)
+
+// Names of the graph input and outputs
+const (
+	InputName         = "input"
+	OutputName        = "variable"
+	LabelName         = "label"
+	ProbabilitiesName = "probabilities"
+)
+
+// ONNX type codes
+const (
+	attributeFloat   = 1
+	attributeInt     = 2
+	attributeString  = 3
+	attributeFloats  = 6
+	attributeInts    = 7
+	attributeStrings = 8
+
+	tensorFloat = 1
+	tensorInt64 = 7
+)
+
+// Export convert m to a serialized ONNX ModelProto
+func Export(m *model.TreeModel) ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if m.NumFeature < 1 {
+		return nil, fmt.Errorf("model has %d features", m.NumFeature)
+	}
+
+	t, err := model.ObjectiveTransform(m.Objective)
+	if err != nil {
+		return nil, err
+	}
+	binary := t == model.TransformSigmoid && strings.HasSuffix(m.Objective, "binary:logistic")
+
+	var graph message
+	var outputs []message
+	ensemble := treeEnsemble(m)
+	switch {
+	case t == model.TransformHinge:
+		return nil, fmt.Errorf("ONNX-ML has no post transform for %s", m.Objective)
+	case binary, t == model.TransformSoftmax, t == model.TransformArgmax:
+		classes := m.NumGroup
+		if binary {
+			classes = 2
+			ensemble.string("post_transform", "LOGISTIC")
+		} else {
+			ensemble.string("post_transform", "SOFTMAX")
+		}
+		labels := make([]int64, classes)
+		for i := range labels {
+			labels[i] = int64(i)
+		}
+		ensemble.ints("classlabels_int64s", labels)
+		ensemble.ints("class_ids", ensemble.targetIDs)
+		ensemble.ints("class_nodeids", ensemble.targetNodes)
+		ensemble.ints("class_treeids", ensemble.targetTrees)
+		ensemble.floats("class_weights", ensemble.targetWeights)
+		graph.message(1, ensemble.node("TreeEnsembleClassifier", []string{InputName}, []string{LabelName, ProbabilitiesName}))
+		outputs = append(outputs, valueInfo(LabelName, tensorInt64), valueInfo(ProbabilitiesName, tensorFloat, int64(classes)))
+	default:
+		transform := "NONE"
+		output := OutputName
+		var exp bool
+		switch t {
+		case model.TransformSigmoid:
+			transform = "LOGISTIC"
+		case model.TransformExp:
+			// ONNX-ML has no exponential post transform, an Exp node follows instead
+			output, exp = "margin", true
+		}
+		ensemble.string("post_transform", transform)
+		ensemble.int("n_targets", int64(m.NumGroup))
+		ensemble.ints("target_ids", ensemble.targetIDs)
+		ensemble.ints("target_nodeids", ensemble.targetNodes)
+		ensemble.ints("target_treeids", ensemble.targetTrees)
+		ensemble.floats("target_weights", ensemble.targetWeights)
+		graph.message(1, ensemble.node("TreeEnsembleRegressor", []string{InputName}, []string{output}))
+		if exp {
+			var node message
+			node.string(1, output)
+			node.string(2, OutputName)
+			node.string(3, "exp")
+			node.string(4, "Exp")
+			graph.message(1, node)
+		}
+		outputs = append(outputs, valueInfo(OutputName, tensorFloat, int64(m.NumGroup)))
+	}
+	graph.string(2, "xgboost")
+	graph.message(11, valueInfo(InputName, tensorFloat, int64(m.NumFeature)))
+	for _, output := range outputs {
+		graph.message(12, output)
+	}
+
+	var onnx message
+	onnx.int(1, irVersion)
+	onnx.string(2, "xgboost-go")
+	onnx.string(6, "xgboost "+m.Objective)
+	onnx.message(7, graph)
+	for _, opset := range []struct {
+		domain  string
+		version int64
+	}{{"", opsetVersion}, {mlDomain, mlOpsetVersion}} {
+		var id message
+		id.string(1, opset.domain)
+		id.int(2, opset.version)
+		onnx.message(8, id)
+	}
+	return onnx, nil
+}
+
+// ensembleNode collect the attributes of a tree ensemble node
+type ensembleNode struct {
+	attributes    []message
+	targetIDs     []int64
+	targetNodes   []int64
+	targetTrees   []int64
+	targetWeights []float32
+}
+
+func (e *ensembleNode) attribute(name string, kind int64, set func(a *message)) {
+	var a message
+	a.string(1, name)
+	set(&a)
+	a.int(20, kind)
+	e.attributes = append(e.attributes, a)
+}
+
+func (e *ensembleNode) string(name string, v string) {
+	e.attribute(name, attributeString, func(a *message) { a.string(4, v) })
+}
+
+func (e *ensembleNode) int(name string, v int64) {
+	e.attribute(name, attributeInt, func(a *message) { a.int(3, v) })
+}
+
+func (e *ensembleNode) ints(name string, v []int64) {
+	e.attribute(name, attributeInts, func(a *message) { a.ints(8, v) })
+}
+
+func (e *ensembleNode) floats(name string, v []float32) {
+	e.attribute(name, attributeFloats, func(a *message) { a.floats(7, v) })
+}
+
+func (e *ensembleNode) strings(name string, v []string) {
+	e.attribute(name, attributeStrings, func(a *message) {
+		for _, s := range v {
+			a.string(9, s)
+		}
+	})
+}
+
+func (e *ensembleNode) node(opType string, inputs []string, outputs []string) message {
+	var node message
+	for _, input := range inputs {
+		node.string(1, input)
+	}
+	for _, output := range outputs {
+		node.string(2, output)
+	}
+	node.string(3, "trees")
+	node.string(4, opType)
+	for _, a := range e.attributes {
+		node.message(5, a)
+	}
+	node.string(7, mlDomain)
+	return node
+}
+
+// treeEnsemble collect the nodes and leaf weights of the trees of m. Leaves of the
+// binary classifier all add to class 0, the score of the positive class.
+func treeEnsemble(m *model.TreeModel) *ensembleNode {
+	e := &ensembleNode{}
+	var treeIDs, nodeIDs, featureIDs, trueIDs, falseIDs, missingTrue []int64
+	var values []float32
+	var modes []string
+	for treeID, tree := range m.Trees {
+		group := int64(m.TreeGroups[treeID])
+		var add func(id int)
+		add = func(id int) {
+			n := &tree.Nodes[id]
+			treeIDs = append(treeIDs, int64(treeID))
+			nodeIDs = append(nodeIDs, int64(id))
+			if n.IsLeaf() {
+				modes = append(modes, "LEAF")
+				featureIDs = append(featureIDs, 0)
+				values = append(values, 0)
+				trueIDs = append(trueIDs, 0)
+				falseIDs = append(falseIDs, 0)
+				missingTrue = append(missingTrue, 0)
+				e.targetTrees = append(e.targetTrees, int64(treeID))
+				e.targetNodes = append(e.targetNodes, int64(id))
+				e.targetIDs = append(e.targetIDs, group)
+				e.targetWeights = append(e.targetWeights, n.Leaf)
+				return
+			}
+			modes = append(modes, "BRANCH_LT")
+			featureIDs = append(featureIDs, int64(n.Feature))
+			values = append(values, n.Threshold)
+			trueIDs = append(trueIDs, int64(n.Yes))
+			falseIDs = append(falseIDs, int64(n.No))
+			if n.Missing == n.Yes {
+				missingTrue = append(missingTrue, 1)
+			} else {
+				missingTrue = append(missingTrue, 0)
+			}
+			add(n.Yes)
+			add(n.No)
+		}
+		add(0)
+	}
+
+	e.string("aggregate_function", "SUM")
+	base := make([]float32, m.NumGroup)
+	for i := range base {
+		base[i] = m.BaseMargin
+	}
+	e.floats("base_values", base)
+	e.ints("nodes_treeids", treeIDs)
+	e.ints("nodes_nodeids", nodeIDs)
+	e.ints("nodes_featureids", featureIDs)
+	e.floats("nodes_values", values)
+	e.strings("nodes_modes", modes)
+	e.ints("nodes_truenodeids", trueIDs)
+	e.ints("nodes_falsenodeids", falseIDs)
+	e.ints("nodes_missing_value_tracks_true", missingTrue)
+	return e
+}
+
+// valueInfo describe a tensor [N, dims...] with a symbolic batch size
+func valueInfo(name string, elemType int64, dims ...int64) message {
+	var shape message
+	var batch message
+	batch.string(2, "N")
+	shape.message(1, batch)
+	for _, d := range dims {
+		var dim message
+		dim.int(1, d)
+		shape.message(1, dim)
+	}
+	var tensor message
+	tensor.int(1, elemType)
+	tensor.message(2, shape)
+	var typ message
+	typ.message(1, tensor)
+	var info message
+	info.string(1, name)
+	info.message(2, typ)
+	return info
+}
@@ -0,0 +1,27 @@
+package onnx
+
+import (
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testbooster"
+)
+
+func TestExportBooster(t *testing.T) {
+	for _, c := range []struct {
+		objective string
+		numClass  int
+	}{
+		{"reg:linear", 1},
+		{"reg:logistic", 2},
+		{"count:poisson", 1},
+		{"binary:logistic", 2},
+		{"multi:softprob", 3},
+		{"multi:softmax", 3},
+	} {
+		t.Run(c.objective, func(t *testing.T) {
+			m, preds := testbooster.Train(t, c.objective, c.numClass)
+			perRow := len(preds) / len(testbooster.Rows)
+			checkExport(t, m, testbooster.Rows, func(i int) []float32 { return preds[i*perRow : (i+1)*perRow] })
+		})
+	}
+}
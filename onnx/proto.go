@@ -0,0 +1,158 @@
+package onnx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendFixed32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+// message encode a protobuf message field by field
+type message []byte
+
+func (m *message) tag(field int, wire int) {
+	*m = appendVarint(*m, uint64(field)<<3|uint64(wire))
+}
+
+func (m *message) int(field int, v int64) {
+	m.tag(field, wireVarint)
+	*m = appendVarint(*m, uint64(v))
+}
+
+func (m *message) float(field int, v float32) {
+	m.tag(field, wireFixed32)
+	*m = appendFixed32(*m, math.Float32bits(v))
+}
+
+func (m *message) bytes(field int, v []byte) {
+	m.tag(field, wireBytes)
+	*m = appendVarint(*m, uint64(len(v)))
+	*m = append(*m, v...)
+}
+
+func (m *message) string(field int, v string) {
+	m.bytes(field, []byte(v))
+}
+
+func (m *message) message(field int, v message) {
+	m.bytes(field, v)
+}
+
+// ints and floats write repeated fields packed, which protobuf parsers accept for
+// proto2 fields too and which keeps large ensembles small
+func (m *message) ints(field int, v []int64) {
+	var packed []byte
+	for _, x := range v {
+		packed = appendVarint(packed, uint64(x))
+	}
+	m.bytes(field, packed)
+}
+
+func (m *message) floats(field int, v []float32) {
+	packed := make([]byte, 0, 4*len(v))
+	for _, x := range v {
+		packed = appendFixed32(packed, math.Float32bits(x))
+	}
+	m.bytes(field, packed)
+}
+
+// field is a decoded protobuf field, value holds varints and fixed values and data
+// length delimited ones
+type field struct {
+	number int
+	wire   int
+	value  uint64
+	data   []byte
+}
+
+var errTruncated = errors.New("onnx: truncated message")
+
+// decode split a protobuf message into its fields
+func decode(data []byte) ([]field, error) {
+	var fields []field
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		data = data[n:]
+		f := field{number: int(key >> 3), wire: int(key & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.value, n = binary.Uvarint(data); n <= 0 {
+				return nil, errTruncated
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return nil, errTruncated
+			}
+			f.value, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return nil, errTruncated
+			}
+			f.value, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			length, n := binary.Uvarint(data)
+			if n <= 0 || length > uint64(len(data)-n) {
+				return nil, errTruncated
+			}
+			f.data, data = data[n:n+int(length)], data[n+int(length):]
+		default:
+			return nil, fmt.Errorf("onnx: unsupported wire type %d", f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// appendInts append the values of a repeated int field, packed or not
+func appendInts(values []int64, f field) ([]int64, error) {
+	if f.wire == wireVarint {
+		return append(values, int64(f.value)), nil
+	}
+	data := f.data
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, errTruncated
+		}
+		values, data = append(values, int64(v)), data[n:]
+	}
+	return values, nil
+}
+
+// appendFloats append the values of a repeated float field, packed or not
+func appendFloats(values []float32, f field) ([]float32, error) {
+	if f.wire == wireFixed32 {
+		return append(values, math.Float32frombits(uint32(f.value))), nil
+	}
+	if len(f.data)%4 != 0 {
+		return nil, errTruncated
+	}
+	for i := 0; i < len(f.data); i += 4 {
+		values = append(values, math.Float32frombits(binary.LittleEndian.Uint32(f.data[i:])))
+	}
+	return values, nil
+}
@@ -0,0 +1,34 @@
+package xgboost
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/liuhaoXD/xgboost-go/plot"
+)
+
+// TreeToDOT render the i-th tree as a Graphviz DOT graph, e.g. for dot -Tsvg
+func (booster *Booster) TreeToDOT(i int, opts plot.Options) (string, error) {
+	m, err := booster.TreeModel()
+	if err != nil {
+		return "", err
+	}
+	if i < 0 || i >= len(m.Trees) {
+		return "", fmt.Errorf("tree %d out of range, the model has %d trees", i, len(m.Trees))
+	}
+	var b bytes.Buffer
+	if err := plot.DOT(&b, fmt.Sprintf("tree%d", i), m.Trees[i], opts); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// TreesToHTML write a self-contained HTML page to browse every tree of the model
+func (booster *Booster) TreesToHTML(w io.Writer, opts plot.Options) error {
+	m, err := booster.TreeModel()
+	if err != nil {
+		return err
+	}
+	return plot.HTML(w, m, opts)
+}
@@ -0,0 +1,168 @@
+// Package plot renders tree models for people: a tree as a Graphviz DOT graph, or the
+// whole ensemble as a self-contained HTML page that needs neither Graphviz nor network
+// access to view.
+package plot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// Options configure how trees are rendered
+type Options struct {
+	// FeatureNames name the features by index, features without a name are called
+	// "f<index>"
+	FeatureNames []string
+	// Stats show the gain of splits and the cover of every node
+	Stats bool
+	// Color shade nodes by "gain" or "cover", relative to the largest in the tree for
+	// DOT and in the ensemble for HTML. Empty leaves nodes unshaded.
+	Color string
+	// Title is the title of the HTML page, "xgboost model" by default
+	Title string
+}
+
+func (opts *Options) check() error {
+	switch opts.Color {
+	case "", "gain", "cover":
+		return nil
+	}
+	return fmt.Errorf("unknown color %q, expected gain or cover", opts.Color)
+}
+
+func (opts *Options) featureName(index int) string {
+	if index < len(opts.FeatureNames) && opts.FeatureNames[index] != "" {
+		return opts.FeatureNames[index]
+	}
+	return "f" + strconv.Itoa(index)
+}
+
+// colorStat get the statistic of n nodes are shaded by, false for nodes left unshaded
+func (opts *Options) colorStat(n *model.Node) (float64, bool) {
+	switch {
+	case opts.Color == "gain" && !n.IsLeaf():
+		return n.Gain, true
+	case opts.Color == "cover":
+		return n.Cover, true
+	}
+	return 0, false
+}
+
+// maxStat get the largest statistic nodes of trees are shaded by
+func (opts *Options) maxStat(trees []*model.Tree) float64 {
+	var max float64
+	for _, tree := range trees {
+		walk(tree, func(n *model.Node, _ int) {
+			if v, ok := opts.colorStat(n); ok && v > max {
+				max = v
+			}
+		})
+	}
+	return max
+}
+
+// fillColor get the color of n shaded relative to max, from white to blue
+func (opts *Options) fillColor(n *model.Node, max float64) string {
+	v, ok := opts.colorStat(n)
+	if !ok || max <= 0 {
+		return "#ffffff"
+	}
+	shade := math.Sqrt(math.Max(0, math.Min(1, v/max)))
+	channel := func(to float64) int { return int(math.Round(255 + (to-255)*shade)) }
+	return fmt.Sprintf("#%02x%02x%02x", channel(0x4a), channel(0x90), channel(0xd9))
+}
+
+// splitLabel describe the split of n
+func (opts *Options) splitLabel(n *model.Node) string {
+	return opts.featureName(n.Feature) + " < " + formatFloat(float64(n.Threshold), 32)
+}
+
+// leafLabel describe the value of leaf n
+func leafLabel(n *model.Node) string {
+	return "leaf = " + formatFloat(float64(n.Leaf), 32)
+}
+
+// statsLabel describe the gain and cover of n
+func statsLabel(n *model.Node) string {
+	if n.IsLeaf() {
+		return "cover = " + formatFloat(n.Cover, 64)
+	}
+	return "gain = " + formatFloat(n.Gain, 64) + ", cover = " + formatFloat(n.Cover, 64)
+}
+
+// edgeLabels get the labels of the yes and no edges of n, missing values going along
+// one of them
+func edgeLabels(n *model.Node) (string, string) {
+	if n.Missing == n.Yes {
+		return "yes, missing", "no"
+	}
+	return "yes", "no, missing"
+}
+
+func formatFloat(v float64, bitSize int) string {
+	if bitSize == 64 {
+		return strconv.FormatFloat(v, 'g', 6, 64)
+	}
+	return strconv.FormatFloat(v, 'g', -1, 32)
+}
+
+// dotQuote quote s as a DOT string, newlines breaking lines of labels
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// walk call fn for the nodes of tree reachable from the root, parents before children
+func walk(tree *model.Tree, fn func(n *model.Node, depth int)) {
+	var visit func(id, depth int)
+	visit = func(id, depth int) {
+		n := &tree.Nodes[id]
+		fn(n, depth)
+		if !n.IsLeaf() {
+			visit(n.Yes, depth+1)
+			visit(n.No, depth+1)
+		}
+	}
+	visit(0, 0)
+}
+
+// DOT write tree as a Graphviz DOT graph named name. Splits are boxes reading
+// "feature < threshold", leaves are ellipses with their value, and the edge of every
+// split missing values follow is labeled so.
+func DOT(w io.Writer, name string, tree *model.Tree, opts Options) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+	max := opts.maxStat([]*model.Tree{tree})
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(name))
+	b.WriteString("\tgraph [rankdir=TB];\n")
+	b.WriteString("\tnode [shape=box, style=\"rounded,filled\", fontname=Helvetica];\n")
+	b.WriteString("\tedge [fontname=Helvetica];\n")
+	walk(tree, func(n *model.Node, _ int) {
+		label, shape := leafLabel(n), ", shape=ellipse"
+		if !n.IsLeaf() {
+			label, shape = opts.splitLabel(n), ""
+		}
+		if opts.Stats {
+			label += "\n" + statsLabel(n)
+		}
+		fmt.Fprintf(&b, "\t%d [label=%s, fillcolor=%q%s];\n", n.ID, dotQuote(label), opts.fillColor(n, max), shape)
+		if !n.IsLeaf() {
+			yes, no := edgeLabels(n)
+			fmt.Fprintf(&b, "\t%d -> %d [label=%q, color=\"#0000ff\"];\n", n.ID, n.Yes, yes)
+			fmt.Fprintf(&b, "\t%d -> %d [label=%q, color=\"#ff0000\"];\n", n.ID, n.No, no)
+		}
+	})
+	b.WriteString("}\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,147 @@
+package plot
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// htmlTree is a tree of the HTML page
+type htmlTree struct {
+	Index int
+	Group int
+	Depth int
+	Root  *htmlNode
+}
+
+// htmlNode is a node of the HTML page with the edge leading to it
+type htmlNode struct {
+	Edge     string
+	EdgeYes  bool
+	Label    string
+	Stats    string
+	Color    template.CSS
+	Leaf     bool
+	Children []*htmlNode
+}
+
+type htmlPage struct {
+	Title      string
+	Objective  string
+	NumFeature int
+	NumGroup   int
+	Trees      []htmlTree
+}
+
+// HTML write a page showing every tree of m as collapsible nested lists. It is self
+// contained, with its style and the script selecting a tree inline.
+func HTML(w io.Writer, m *model.TreeModel, opts Options) error {
+	if err := opts.check(); err != nil {
+		return err
+	}
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	page := htmlPage{
+		Title:      opts.Title,
+		Objective:  m.Objective,
+		NumFeature: m.NumFeature,
+		NumGroup:   m.NumGroup,
+		Trees:      make([]htmlTree, len(m.Trees)),
+	}
+	if page.Title == "" {
+		page.Title = "xgboost model"
+	}
+	max := opts.maxStat(m.Trees)
+	for i, tree := range m.Trees {
+		page.Trees[i] = htmlTree{Index: i, Group: m.TreeGroups[i], Depth: tree.MaxDepth(), Root: opts.htmlNode(tree, 0, "", max)}
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+func (opts *Options) htmlNode(tree *model.Tree, id int, edge string, max float64) *htmlNode {
+	n := &tree.Nodes[id]
+	node := &htmlNode{Edge: edge, Color: template.CSS("background-color: " + opts.fillColor(n, max))}
+	if opts.Stats {
+		node.Stats = statsLabel(n)
+	}
+	if n.IsLeaf() {
+		node.Label = leafLabel(n)
+		node.Leaf = true
+		return node
+	}
+	node.Label = opts.splitLabel(n)
+	yes, no := edgeLabels(n)
+	node.Children = []*htmlNode{opts.htmlNode(tree, n.Yes, yes, max), opts.htmlNode(tree, n.No, no, max)}
+	node.Children[0].EdgeYes = true
+	return node
+}
+
+var htmlTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"plural": func(n int, one, many string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, one)
+		}
+		return fmt.Sprintf("%d %s", n, many)
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+header p { color: #666; }
+section { display: none; }
+section.shown { display: block; }
+ul { list-style: none; margin: 0; padding-left: 1.5em; border-left: 1px dashed #bbb; }
+ul.root { border-left: none; padding-left: 0; }
+li { margin: 0.3em 0; }
+summary { cursor: pointer; }
+.node { display: inline-block; padding: 0.15em 0.5em; border: 1px solid #888; border-radius: 4px; }
+.leaf { border-radius: 1em; }
+.edge { font-size: 0.8em; margin-right: 0.4em; }
+.edge.yes { color: #0000ff; }
+.edge.no { color: #ff0000; }
+.stats { font-size: 0.8em; color: #555; margin-left: 0.4em; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<p>{{if .Objective}}{{.Objective}}, {{end}}{{plural (len .Trees) "tree" "trees"}}, {{plural .NumFeature "feature" "features"}}{{if gt .NumGroup 1}}, {{plural .NumGroup "class" "classes"}}{{end}}</p>
+<label>Tree <select id="tree">
+{{- range .Trees}}
+<option value="{{.Index}}">{{.Index}}{{if gt $.NumGroup 1}} (class {{.Group}}){{end}}, depth {{.Depth}}</option>
+{{- end}}
+</select></label>
+</header>
+{{range .Trees}}
+<section id="tree-{{.Index}}"{{if eq .Index 0}} class="shown"{{end}}>
+<h2>Tree {{.Index}}</h2>
+<ul class="root">{{template "node" .Root}}</ul>
+</section>
+{{end}}
+<script>
+document.getElementById("tree").addEventListener("change", function (e) {
+	document.querySelector("section.shown").classList.remove("shown");
+	document.getElementById("tree-" + e.target.value).classList.add("shown");
+});
+</script>
+</body>
+</html>
+{{define "node"}}<li>
+{{- if .Leaf}}{{template "label" .}}
+{{- else}}<details open><summary>{{template "label" .}}</summary><ul>
+{{- range .Children}}{{template "node" .}}{{end -}}
+</ul></details>
+{{- end}}</li>
+{{end}}
+{{define "label"}}
+{{- if .Edge}}<span class="edge {{if .EdgeYes}}yes{{else}}no{{end}}">{{.Edge}}</span>{{end -}}
+<span class="node{{if .Leaf}} leaf{{end}}" style="{{.Color}}">{{.Label}}</span>
+{{- if .Stats}}<span class="stats">{{.Stats}}</span>{{end}}
+{{- end}}
+`))
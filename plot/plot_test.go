@@ -0,0 +1,95 @@
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testmodel"
+)
+
+func TestDOT(t *testing.T) {
+	m := testmodel.Model(t, "multi:softprob", 2)
+	var b bytes.Buffer
+	opts := Options{FeatureNames: []string{"age"}, Stats: true, Color: "gain"}
+	if err := DOT(&b, "tree0", m.Trees[0], opts); err != nil {
+		t.Fatal(err)
+	}
+	dot := b.String()
+	for _, want := range []string{
+		"digraph \"tree0\" {\n",
+		"\t0 [label=\"age < 2.5\\ngain = 40, cover = 100\", fillcolor=\"#4a90d9\"];\n",
+		"\t1 [label=\"f1 < -1\\ngain = 10, cover = 60\", fillcolor=\"#a5c8ec\"];\n",
+		"\t0 -> 1 [label=\"yes\", color=\"#0000ff\"];\n",
+		"\t0 -> 2 [label=\"no, missing\", color=\"#ff0000\"];\n",
+		"\t1 -> 3 [label=\"yes, missing\", color=\"#0000ff\"];\n",
+		"\t3 [label=\"leaf = 0.4\\ncover = 20\", fillcolor=\"#ffffff\", shape=ellipse];\n",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT has no %q:\n%s", want, dot)
+		}
+	}
+	if !strings.HasSuffix(dot, "}\n") {
+		t.Errorf("DOT is not closed:\n%s", dot)
+	}
+
+	b.Reset()
+	if err := DOT(&b, "x\"y", m.Trees[1], Options{FeatureNames: []string{"a\"b"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "digraph \"x\\\"y\" {\n"; !strings.HasPrefix(b.String(), want) {
+		t.Errorf("DOT starts with %q, expected %q", b.String(), want)
+	}
+	if err := DOT(&b, "tree0", m.Trees[0], Options{Color: "depth"}); err == nil {
+		t.Error("expected an error for an unknown color")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var b bytes.Buffer
+	opts := Options{FeatureNames: []string{"<age>"}, Stats: true, Color: "cover", Title: "churn"}
+	if err := HTML(&b, testmodel.Model(t, "multi:softprob", 2), opts); err != nil {
+		t.Fatal(err)
+	}
+	page := b.String()
+	for _, want := range []string{
+		"<title>churn</title>",
+		"multi:softprob, 3 trees, 3 features, 2 classes",
+		`<option value="1">1 (class 1), depth 1</option>`,
+		`&lt;age&gt; &lt; 2.5`,
+		`<span class="edge no">no, missing</span>`,
+		`style="background-color: #4a90d9"`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("HTML has no %q", want)
+		}
+	}
+
+	// the page is well formed, each tree a section with a node per tree node
+	decoder := xml.NewDecoder(strings.NewReader(strings.TrimPrefix(page, "<!DOCTYPE html>\n")))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+	nodes := map[string]int{}
+	section := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			for _, attr := range start.Attr {
+				switch {
+				case start.Name.Local == "section" && attr.Name.Local == "id":
+					section = attr.Value
+				case start.Name.Local == "span" && attr.Name.Local == "class" && strings.HasPrefix(attr.Value, "node"):
+					nodes[section]++
+				}
+			}
+		}
+	}
+	if nodes["tree-0"] != 5 || nodes["tree-1"] != 3 || nodes["tree-2"] != 1 {
+		t.Errorf("got nodes %v, expected 5, 3 and 1 in the trees", nodes)
+	}
+}
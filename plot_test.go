@@ -0,0 +1,42 @@
+package xgboost
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/plot"
+)
+
+func TestTreeToDOT(t *testing.T) {
+	dm, _ := shapTestData(t, 1)
+	defer dm.Free()
+	booster, err := Train(Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"}, dm, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+
+	opts := plot.Options{FeatureNames: []string{"age", "income", "tenure"}, Stats: true, Color: "gain"}
+	dot, err := booster.TreeToDOT(2, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot, "digraph \"tree2\" {\n") || !strings.Contains(dot, "\t0 [label=") {
+		t.Errorf("unexpected DOT:\n%s", dot)
+	}
+	if !strings.Contains(dot, "missing\"") {
+		t.Errorf("DOT has no missing value edge:\n%s", dot)
+	}
+	if _, err := booster.TreeToDOT(3, opts); err == nil {
+		t.Error("expected an error for a tree out of range")
+	}
+
+	var b bytes.Buffer
+	if err := booster.TreesToHTML(&b, opts); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(b.String(), "<section id=\"tree-"); n != 3 {
+		t.Errorf("HTML has %d trees, expected 3", n)
+	}
+}
@@ -0,0 +1,343 @@
+// Package pmml exports tree models as PMML 4.4 documents, for scoring engines such as
+// JPMML. A model becomes a MiningModel chaining two steps: for every output group a
+// sum of TreeModel segments plus the base margin, then a RegressionModel applying the
+// output transform of the objective to the group margins.
+package pmml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// Options name the fields of the exported model
+type Options struct {
+	// FeatureNames name the features by index, features without a name are called
+	// "f<index>"
+	FeatureNames []string
+	// TargetName is the name of the predicted field, "y" by default
+	TargetName string
+}
+
+type document struct {
+	XMLName        xml.Name       `xml:"PMML"`
+	Namespace      string         `xml:"xmlns,attr"`
+	Version        string         `xml:"version,attr"`
+	Header         header         `xml:"Header"`
+	DataDictionary dataDictionary `xml:"DataDictionary"`
+	MiningModel    miningModel    `xml:"MiningModel"`
+}
+
+type header struct {
+	Application application `xml:"Application"`
+}
+
+type application struct {
+	Name string `xml:"name,attr"`
+}
+
+type dataDictionary struct {
+	NumberOfFields int         `xml:"numberOfFields,attr"`
+	DataFields     []dataField `xml:"DataField"`
+}
+
+type dataField struct {
+	Name     string  `xml:"name,attr"`
+	Optype   string  `xml:"optype,attr"`
+	DataType string  `xml:"dataType,attr"`
+	Values   []value `xml:"Value"`
+}
+
+type value struct {
+	Value string `xml:"value,attr"`
+}
+
+type miningSchema struct {
+	Fields []miningField `xml:"MiningField"`
+}
+
+type miningField struct {
+	Name      string `xml:"name,attr"`
+	UsageType string `xml:"usageType,attr,omitempty"`
+}
+
+type output struct {
+	Fields []outputField `xml:"OutputField"`
+}
+
+type outputField struct {
+	Name          string `xml:"name,attr"`
+	Optype        string `xml:"optype,attr"`
+	DataType      string `xml:"dataType,attr"`
+	Feature       string `xml:"feature,attr"`
+	Value         string `xml:"value,attr,omitempty"`
+	IsFinalResult string `xml:"isFinalResult,attr,omitempty"`
+}
+
+type targets struct {
+	Targets []target `xml:"Target"`
+}
+
+type target struct {
+	Field           string `xml:"field,attr,omitempty"`
+	RescaleConstant string `xml:"rescaleConstant,attr"`
+}
+
+type miningModel struct {
+	FunctionName string       `xml:"functionName,attr"`
+	MiningSchema miningSchema `xml:"MiningSchema"`
+	Output       *output      `xml:"Output"`
+	Targets      *targets     `xml:"Targets"`
+	Segmentation segmentation `xml:"Segmentation"`
+}
+
+type segmentation struct {
+	MultipleModelMethod string    `xml:"multipleModelMethod,attr"`
+	Segments            []segment `xml:"Segment"`
+}
+
+type segment struct {
+	ID              string           `xml:"id,attr"`
+	True            struct{}         `xml:"True"`
+	MiningModel     *miningModel     `xml:"MiningModel"`
+	TreeModel       *treeModel       `xml:"TreeModel"`
+	RegressionModel *regressionModel `xml:"RegressionModel"`
+}
+
+type treeModel struct {
+	FunctionName         string       `xml:"functionName,attr"`
+	MissingValueStrategy string       `xml:"missingValueStrategy,attr"`
+	NoTrueChildStrategy  string       `xml:"noTrueChildStrategy,attr"`
+	SplitCharacteristic  string       `xml:"splitCharacteristic,attr"`
+	MiningSchema         miningSchema `xml:"MiningSchema"`
+	Node                 node         `xml:"Node"`
+}
+
+// node is a tree node, Predicate is nil for the root which is always true
+type node struct {
+	ID           string     `xml:"id,attr"`
+	Score        string     `xml:"score,attr,omitempty"`
+	DefaultChild string     `xml:"defaultChild,attr,omitempty"`
+	True         *struct{}  `xml:"True"`
+	Predicate    *predicate `xml:"SimplePredicate"`
+	Nodes        []node     `xml:"Node"`
+}
+
+type predicate struct {
+	Field    string `xml:"field,attr"`
+	Operator string `xml:"operator,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+type regressionModel struct {
+	FunctionName        string            `xml:"functionName,attr"`
+	NormalizationMethod string            `xml:"normalizationMethod,attr"`
+	MiningSchema        miningSchema      `xml:"MiningSchema"`
+	Output              *output           `xml:"Output"`
+	Tables              []regressionTable `xml:"RegressionTable"`
+}
+
+type regressionTable struct {
+	Intercept      string             `xml:"intercept,attr"`
+	TargetCategory string             `xml:"targetCategory,attr,omitempty"`
+	Predictors     []numericPredictor `xml:"NumericPredictor"`
+}
+
+type numericPredictor struct {
+	Name        string `xml:"name,attr"`
+	Coefficient string `xml:"coefficient,attr"`
+}
+
+func formatFloat(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+// Export write m as a PMML document to w
+func Export(w io.Writer, m *model.TreeModel, opts Options) error {
+	doc, err := newDocument(m, opts)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newDocument(m *model.TreeModel, opts Options) (*document, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	names := make([]string, m.NumFeature)
+	for i := range names {
+		names[i] = "f" + strconv.Itoa(i)
+		if i < len(opts.FeatureNames) && opts.FeatureNames[i] != "" {
+			names[i] = opts.FeatureNames[i]
+		}
+	}
+	for _, tree := range m.Trees {
+		for i := range tree.Nodes {
+			if tree.Nodes[i].Feature >= len(names) {
+				return nil, fmt.Errorf("split on feature %d of %d", tree.Nodes[i].Feature, len(names))
+			}
+		}
+	}
+	targetName := opts.TargetName
+	if targetName == "" {
+		targetName = "y"
+	}
+
+	t, err := model.ObjectiveTransform(m.Objective)
+	if err != nil {
+		return nil, err
+	}
+	classes := 0
+	normalization := "none"
+	switch t {
+	case model.TransformSigmoid:
+		normalization = "logit"
+		if strings.HasSuffix(m.Objective, "binary:logistic") {
+			classes = 2
+		}
+	case model.TransformSoftmax, model.TransformArgmax:
+		classes, normalization = m.NumGroup, "softmax"
+	case model.TransformExp:
+		normalization = "exp"
+	case model.TransformHinge:
+		return nil, fmt.Errorf("PMML has no normalization for %s", m.Objective)
+	}
+	functionName := "regression"
+	if classes > 0 {
+		functionName = "classification"
+	} else if m.NumGroup > 1 {
+		return nil, fmt.Errorf("cannot export %d outputs of a %s model", m.NumGroup, m.Objective)
+	}
+
+	doc := &document{
+		Namespace: "http://www.dmg.org/PMML-4_4",
+		Version:   "4.4",
+		Header:    header{Application: application{Name: "xgboost-go"}},
+	}
+	var featureFields []miningField
+	for _, name := range names {
+		doc.DataDictionary.DataFields = append(doc.DataDictionary.DataFields, dataField{Name: name, Optype: "continuous", DataType: "float"})
+		featureFields = append(featureFields, miningField{Name: name})
+	}
+	targetField := dataField{Name: targetName, Optype: "continuous", DataType: "float"}
+	if classes > 0 {
+		targetField = dataField{Name: targetName, Optype: "categorical", DataType: "integer"}
+		for c := 0; c < classes; c++ {
+			targetField.Values = append(targetField.Values, value{strconv.Itoa(c)})
+		}
+	}
+	doc.DataDictionary.DataFields = append(doc.DataDictionary.DataFields, targetField)
+	doc.DataDictionary.NumberOfFields = len(doc.DataDictionary.DataFields)
+
+	// one sum of trees per group, each output as a margin field
+	chain := segmentation{MultipleModelMethod: "modelChain"}
+	var margins []numericPredictor
+	var marginFields []miningField
+	for group := 0; group < m.NumGroup; group++ {
+		margin := fmt.Sprintf("margin(%d)", group)
+		sum := &miningModel{
+			FunctionName: "regression",
+			MiningSchema: miningSchema{Fields: featureFields},
+			Output: &output{Fields: []outputField{
+				{Name: margin, Optype: "continuous", DataType: "float", Feature: "predictedValue", IsFinalResult: "false"},
+			}},
+			Targets:      &targets{Targets: []target{{RescaleConstant: formatFloat(m.BaseMargin)}}},
+			Segmentation: segmentation{MultipleModelMethod: "sum"},
+		}
+		for i, tree := range m.Trees {
+			if m.TreeGroups[i] != group {
+				continue
+			}
+			sum.Segmentation.Segments = append(sum.Segmentation.Segments, segment{
+				ID: strconv.Itoa(i + 1),
+				TreeModel: &treeModel{
+					FunctionName:         "regression",
+					MissingValueStrategy: "defaultChild",
+					NoTrueChildStrategy:  "returnLastPrediction",
+					SplitCharacteristic:  "binarySplit",
+					MiningSchema:         miningSchema{Fields: featureFields},
+					Node:                 treeNode(tree, 0, names, nil),
+				},
+			})
+		}
+		chain.Segments = append(chain.Segments, segment{ID: fmt.Sprintf("margin%d", group), MiningModel: sum})
+		margins = append(margins, numericPredictor{Name: margin, Coefficient: "1"})
+		marginFields = append(marginFields, miningField{Name: margin})
+	}
+
+	// the output transform of the objective
+	transform := &regressionModel{
+		FunctionName:        functionName,
+		NormalizationMethod: normalization,
+		MiningSchema:        miningSchema{Fields: append([]miningField{{Name: targetName, UsageType: "target"}}, marginFields...)},
+	}
+	switch {
+	case classes == 2:
+		// with logit, the second table gets one minus the probability of the first
+		transform.Tables = []regressionTable{
+			{Intercept: "0", TargetCategory: "1", Predictors: margins},
+			{Intercept: "0", TargetCategory: "0"},
+		}
+	case classes > 2:
+		for c := 0; c < classes; c++ {
+			transform.Tables = append(transform.Tables, regressionTable{Intercept: "0", TargetCategory: strconv.Itoa(c), Predictors: margins[c : c+1]})
+		}
+	default:
+		transform.Tables = []regressionTable{{Intercept: "0", Predictors: margins}}
+	}
+	if classes > 0 {
+		transform.Output = &output{}
+		for c := 0; c < classes; c++ {
+			transform.Output.Fields = append(transform.Output.Fields, outputField{
+				Name:     fmt.Sprintf("probability(%d)", c),
+				Optype:   "continuous",
+				DataType: "float",
+				Feature:  "probability",
+				Value:    strconv.Itoa(c),
+			})
+		}
+	}
+	chain.Segments = append(chain.Segments, segment{ID: "transform", RegressionModel: transform})
+
+	doc.MiningModel = miningModel{
+		FunctionName: functionName,
+		MiningSchema: miningSchema{Fields: append([]miningField{{Name: targetName, UsageType: "target"}}, featureFields...)},
+		Segmentation: chain,
+	}
+	return doc, nil
+}
+
+// treeNode convert node id of tree and its subtree, with the predicate that leads to it
+// from its parent
+func treeNode(tree *model.Tree, id int, names []string, pred *predicate) node {
+	n := &tree.Nodes[id]
+	out := node{ID: strconv.Itoa(id), Predicate: pred}
+	if pred == nil {
+		out.True = &struct{}{}
+	}
+	if n.IsLeaf() {
+		out.Score = formatFloat(n.Leaf)
+		return out
+	}
+	out.DefaultChild = strconv.Itoa(n.Missing)
+	threshold := formatFloat(n.Threshold)
+	out.Nodes = []node{
+		treeNode(tree, n.Yes, names, &predicate{Field: names[n.Feature], Operator: "lessThan", Value: threshold}),
+		treeNode(tree, n.No, names, &predicate{Field: names[n.Feature], Operator: "greaterOrEqual", Value: threshold}),
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package pmml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testmodel"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// score evaluate the subset of PMML written by Export, returning the predicted value
+// of regressions and the class probabilities of classifications
+func score(t *testing.T, doc *document, row []float32) []float64 {
+	index := map[string]int{}
+	for i, f := range doc.DataDictionary.DataFields {
+		index[f.Name] = i
+	}
+	fields := map[string]float64{}
+	var result []float64
+	for _, s := range doc.MiningModel.Segmentation.Segments {
+		switch {
+		case s.MiningModel != nil:
+			sum, err := strconv.ParseFloat(s.MiningModel.Targets.Targets[0].RescaleConstant, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tree := range s.MiningModel.Segmentation.Segments {
+				sum += scoreNode(t, &tree.TreeModel.Node, index, row)
+			}
+			fields[s.MiningModel.Output.Fields[0].Name] = sum
+		case s.RegressionModel != nil:
+			for _, table := range s.RegressionModel.Tables {
+				var y float64
+				for _, p := range table.Predictors {
+					y += fields[p.Name]
+				}
+				result = append(result, y)
+			}
+			switch s.RegressionModel.NormalizationMethod {
+			case "logit":
+				result[0] = 1 / (1 + math.Exp(-result[0]))
+				if len(result) == 2 {
+					result[1] = 1 - result[0]
+				}
+			case "exp":
+				result[0] = math.Exp(result[0])
+			case "softmax":
+				var total float64
+				for i := range result {
+					result[i] = math.Exp(result[i])
+					total += result[i]
+				}
+				for i := range result {
+					result[i] /= total
+				}
+			}
+		}
+	}
+	return result
+}
+
+func scoreNode(t *testing.T, n *node, index map[string]int, row []float32) float64 {
+	if len(n.Nodes) == 0 {
+		v, err := strconv.ParseFloat(n.Score, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		x := row[index[child.Predicate.Field]]
+		threshold, err := strconv.ParseFloat(child.Predicate.Value, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		match := child.ID == n.DefaultChild
+		if x == x {
+			match = (child.Predicate.Operator == "lessThan") == (x < float32(threshold))
+		}
+		if match {
+			return scoreNode(t, child, index, row)
+		}
+	}
+	t.Fatalf("no child of node %s matches", n.ID)
+	return 0
+}
+
+func TestExport(t *testing.T) {
+	for _, c := range []struct {
+		golden    string
+		objective string
+		numGroup  int
+	}{
+		{"regression.pmml", "reg:linear", 1},
+		{"poisson.pmml", "count:poisson", 1},
+		{"binary.pmml", "binary:logistic", 1},
+		{"multiclass.pmml", "multi:softprob", 3},
+	} {
+		t.Run(c.golden, func(t *testing.T) {
+			m := testmodel.Model(t, c.objective, c.numGroup)
+			var buf bytes.Buffer
+			if err := Export(&buf, m, Options{FeatureNames: []string{"age", "balance"}, TargetName: "label"}); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", c.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(buf.Bytes(), want) {
+				t.Errorf("export differs from %s, run go test -update to see the difference", golden)
+			}
+
+			doc := &document{}
+			if err := xml.Unmarshal(buf.Bytes(), doc); err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range testmodel.Rows {
+				want, err := m.Predict(row)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got := score(t, doc, row)
+				if c.objective == "binary:logistic" {
+					got = got[:1]
+				}
+				for i := range want {
+					if math.Abs(got[i]-float64(want[i])) > 1e-6 {
+						t.Errorf("row %v: PMML scores %v, model predicts %v", row, got, want)
+					}
+				}
+			}
+		})
+	}
+
+	m := testmodel.Model(t, "reg:linear", 1)
+	m.NumFeature = 2
+	if err := Export(ioutil.Discard, m, Options{}); err == nil {
+		t.Error("expected an error for a split on a feature out of range")
+	}
+	for _, objective := range []string{"binary:hinge", "reg:unknown"} {
+		if err := Export(ioutil.Discard, testmodel.Model(t, objective, 1), Options{}); err == nil {
+			t.Errorf("expected an error exporting a %s model", objective)
+		}
+	}
+}
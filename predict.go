@@ -0,0 +1,93 @@
+package xgboost
+
+// #cgo LDFLAGS: -L${SRCDIR}/lib -lxgboost -lrabit -ldmlc -lstdc++ -lz -lrt -lm -lpthread -fopenmp
+// #cgo CFLAGS: -I ${SRCDIR}/lib/xgboost/
+// #include <stdlib.h>
+// #include "c_api.h"
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"unsafe"
+)
+
+// PredictRow predict a dense row, NaN features are missing. It saves the caller
+// creating and freeing a DMatrix; optionMask and ntreeLimit are those of Predict.
+func (booster *Booster) PredictRow(row []float32, optionMask int, ntreeLimit uint) ([]float32, error) {
+	return booster.PredictDense(row, 1, len(row), optionMask, ntreeLimit)
+}
+
+// PredictDense predict rows of cols features stored one row after the other in data,
+// NaN features are missing. data is handed to libxgboost without a copy.
+func (booster *Booster) PredictDense(data []float32, rows, cols int, optionMask int, ntreeLimit uint) ([]float32, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, fmt.Errorf("invalid shape of %d rows of %d columns", rows, cols)
+	}
+	if len(data) != rows*cols {
+		return nil, fmt.Errorf("got %d values for %d rows of %d columns", len(data), rows, cols)
+	}
+	var handle C.DMatrixHandle
+	ret := C.XGDMatrixCreateFromMat((*C.float)(unsafe.Pointer(&data[0])), C.bst_ulong(rows), C.bst_ulong(cols), C.float(math.NaN()), &handle)
+	if err := checkError(ret); err != nil {
+		return nil, err
+	}
+	defer C.XGDMatrixFree(handle)
+	return booster.predictHandle(handle, optionMask, ntreeLimit)
+}
+
+// PredictSparse predict compressed sparse rows, given as to DMatrixCreateFromCSREx
+func (booster *Booster) PredictSparse(indptr []uint64, indices []uint32, data []float32, numCol int, optionMask int, ntreeLimit uint) ([]float32, error) {
+	dMatrix, err := DMatrixCreateFromCSREx(indptr, indices, data, numCol)
+	if err != nil {
+		return nil, err
+	}
+	defer dMatrix.Free()
+	return booster.predictHandle(dMatrix.handle, optionMask, ntreeLimit)
+}
+
+func (booster *Booster) predictHandle(handle C.DMatrixHandle, optionMask int, ntreeLimit uint) ([]float32, error) {
+	var (
+		outPtr *C.float
+		outLen C.bst_ulong
+	)
+	ret := C.XGBoosterPredict(booster.handle, handle, C.int(optionMask), C.unsigned(ntreeLimit), &outLen, &outPtr)
+	if err := checkError(ret); err != nil {
+		return nil, err
+	}
+	result := make([]float32, int(outLen))
+	copyFloats(result, unsafe.Pointer(outPtr))
+	return result, nil
+}
+
+// copyChunk is the number of floats copyFloats copies at a time
+const copyChunk = 1 << 20
+
+// copyFloats copy len(dst) floats from src, in chunks so that arbitrarily long outputs
+// never need an array type larger than one chunk
+func copyFloats(dst []float32, src unsafe.Pointer) {
+	for len(dst) > 0 {
+		n := len(dst)
+		if n > copyChunk {
+			n = copyChunk
+		}
+		copy(dst[:n], (*[copyChunk]float32)(src)[:n:n])
+		dst = dst[n:]
+		src = unsafe.Pointer(uintptr(src) + uintptr(n)*unsafe.Sizeof(float32(0)))
+	}
+}
+
+// floatBuffers hold the buffers matrices are flattened into for libxgboost, which
+// copies them, so that they are reused across calls
+var floatBuffers = sync.Pool{New: func() interface{} { return new([]float32) }}
+
+// getFloatBuffer get a buffer of n floats from the pool, to be put back after use
+func getFloatBuffer(n int) *[]float32 {
+	buf := floatBuffers.Get().(*[]float32)
+	if cap(*buf) < n {
+		*buf = make([]float32, n)
+	}
+	*buf = (*buf)[:n]
+	return buf
+}
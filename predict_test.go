@@ -0,0 +1,167 @@
+package xgboost
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"unsafe"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+func TestPredictDense(t *testing.T) {
+	dm, rows := shapTestData(t, 3)
+	defer dm.Free()
+	booster, err := Train(Params{"objective": "multi:softprob", "num_class": "3", "max_depth": "3", "silent": "1"}, dm, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+
+	flat := make([]float32, 0, len(rows)*3)
+	indptr := []uint64{0}
+	var indices []uint32
+	var values []float32
+	for _, row := range rows {
+		flat = append(flat, row...)
+		for j, v := range row {
+			if !math.IsNaN(float64(v)) {
+				indices = append(indices, uint32(j))
+				values = append(values, v)
+			}
+		}
+		indptr = append(indptr, uint64(len(values)))
+	}
+
+	for _, mask := range []int{0, 1, 4} {
+		want, err := booster.Predict(dm, mask, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := booster.PredictDense(flat, len(rows), 3, mask, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPredictions(t, "dense", got, want)
+		got, err = booster.PredictSparse(indptr, indices, values, 3, mask, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkPredictions(t, "sparse", got, want)
+
+		perRow := len(want) / len(rows)
+		for i, row := range rows {
+			got, err := booster.PredictRow(row, mask, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkPredictions(t, "row", got, want[i*perRow:(i+1)*perRow])
+		}
+	}
+
+	if _, err := booster.PredictDense(flat, len(rows), 4, 0, 0); err == nil {
+		t.Error("expected an error for a wrong shape")
+	}
+	if _, err := booster.PredictRow(nil, 0, 0); err == nil {
+		t.Error("expected an error for an empty row")
+	}
+}
+
+func TestCopyFloats(t *testing.T) {
+	// outputs spanning several chunks, ending inside one
+	for _, n := range []int{0, 1, copyChunk, 3*copyChunk + 7} {
+		src := make([]float32, n)
+		for i := range src {
+			src[i] = float32(i)
+		}
+		dst := make([]float32, n)
+		if n > 0 {
+			copyFloats(dst, unsafe.Pointer(&src[0]))
+		}
+		for i := range dst {
+			if dst[i] != src[i] {
+				t.Fatalf("%d floats: copied %v at %d, expected %v", n, dst[i], i, src[i])
+			}
+		}
+	}
+}
+
+func checkPredictions(t *testing.T, name string, got, want []float32) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d predictions, expected %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s: prediction %d is %v, expected %v", name, i, got[i], want[i])
+		}
+	}
+}
+
+func benchmarkBooster(b *testing.B) (*Booster, model.Matrix) {
+	rows := make(model.Matrix, 100)
+	labels := make([]float32, len(rows))
+	for i := range rows {
+		rows[i] = make([]float32, 20)
+		for j := range rows[i] {
+			rows[i][j] = float32((i*7 + j*13) % 17)
+		}
+		labels[i] = float32(i % 2)
+	}
+	dm, err := DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer dm.Free()
+	if err := dm.SetLabels(labels); err != nil {
+		b.Fatal(err)
+	}
+	booster, err := Train(Params{"objective": "binary:logistic", "max_depth": "6", "silent": "1"}, dm, 50)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return booster, rows
+}
+
+// BenchmarkPredict compare predicting through a DMatrix of the caller with PredictDense
+func BenchmarkPredict(b *testing.B) {
+	booster, rows := benchmarkBooster(b)
+	defer booster.Free()
+	for _, n := range []int{1, 100} {
+		batch := rows[:n]
+		flat := make([]float32, 0, n*len(rows[0]))
+		for _, row := range batch {
+			flat = append(flat, row...)
+		}
+
+		b.Run(fmt.Sprintf("DMatrix/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				dm, err := DMatrixCreateFromMat(batch, float32(math.NaN()))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := booster.Predict(dm, 0, 0); err != nil {
+					b.Fatal(err)
+				}
+				dm.Free()
+			}
+		})
+		b.Run(fmt.Sprintf("Dense/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := booster.PredictDense(flat, n, len(rows[0]), 0, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	b.Run("Row", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := booster.PredictRow(rows[i%len(rows)], 0, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package xgboost
+
+import "fmt"
+
+// Truncate get a new booster of the trees of the first numRounds rounds, which predicts
+// like this one with an ntreeLimit of numRounds; 0 keeps every tree. The new booster
+// saves a smaller model, and must be freed.
+func (booster *Booster) Truncate(numRounds int) (*Booster, error) {
+	if numRounds < 0 {
+		return nil, fmt.Errorf("invalid number of rounds %d", numRounds)
+	}
+	return booster.selectTrees(func(params *LearnerParams) ([]int, error) {
+		numGroup := 1
+		if params.NumClass > 1 {
+			numGroup = params.NumClass
+		}
+		n := len(params.TreeGroups)
+		if numRounds > 0 && numRounds*numGroup < n {
+			n = numRounds * numGroup
+		}
+		keep := make([]int, n)
+		for i := range keep {
+			keep[i] = i
+		}
+		return keep, nil
+	})
+}
+
+// PruneTrees get a new booster without the trees whose splits gain less than minGain
+// in total, single leaves included, like TreeModel().PruneTrees(minGain). The new
+// booster must be freed.
+func (booster *Booster) PruneTrees(minGain float64) (*Booster, error) {
+	return booster.selectTrees(func(*LearnerParams) ([]int, error) {
+		m, err := booster.TreeModel()
+		if err != nil {
+			return nil, err
+		}
+		var keep []int
+		for i, tree := range m.Trees {
+			if tree.TotalGain() >= minGain {
+				keep = append(keep, i)
+			}
+		}
+		return keep, nil
+	})
+}
+
+// selectTrees get a new booster of the trees of the raw model at the indexes given by
+// keep, in order
+func (booster *Booster) selectTrees(keep func(params *LearnerParams) ([]int, error)) (*Booster, error) {
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return nil, err
+	}
+	params, learner, rest, err := splitLearner(raw)
+	if err != nil {
+		return nil, err
+	}
+	if params.Booster != "gbtree" {
+		return nil, fmt.Errorf("cannot select the trees of a %s booster", params.Booster)
+	}
+	saved, err := readGBTree(rest)
+	if err != nil {
+		return nil, fmt.Errorf("read trees: %v", err)
+	}
+	params.TreeGroups = saved.groups
+	indexes, err := keep(params)
+	if err != nil {
+		return nil, err
+	}
+
+	model := append(append([]byte{}, learner...), saved.bytes(indexes)...)
+	selected, err := BoosterCreate(nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := selected.LoadModelFromBuffer(model); err != nil {
+		selected.Free()
+		return nil, err
+	}
+	// the recorded round count is stale, BoostedRounds counts the trees left instead
+	if err := selected.DeleteAttr(boostedRoundsAttr); err != nil {
+		selected.Free()
+		return nil, err
+	}
+	return selected, nil
+}
@@ -0,0 +1,122 @@
+package xgboost
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		numClass int
+		params   Params
+	}{
+		{"regression", 1, Params{"objective": "reg:linear"}},
+		{"multiclass", 3, Params{"objective": "multi:softprob", "num_class": "3"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			dm, _ := shapTestData(t, c.numClass)
+			defer dm.Free()
+			c.params["max_depth"] = "3"
+			c.params["silent"] = "1"
+			booster, err := Train(c.params, dm, 6)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer booster.Free()
+
+			truncated, err := booster.Truncate(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer truncated.Free()
+			want, err := booster.Predict(dm, 0, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := truncated.Predict(dm, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("got %d predictions, expected %d", len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("prediction %d: truncated %v, limited %v", i, got[i], want[i])
+				}
+			}
+
+			params, err := truncated.LearnerParams()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(params.TreeGroups) != 2*c.numClass {
+				t.Errorf("truncated model has %d trees, expected %d", len(params.TreeGroups), 2*c.numClass)
+			}
+			rounds, err := truncated.BoostedRounds(c.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rounds != 2 {
+				t.Errorf("truncated model has %d boosted rounds, expected 2", rounds)
+			}
+			names, err := truncated.GetAttrNames()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range names {
+				if name == boostedRoundsAttr {
+					t.Errorf("truncated model keeps the %s attribute", boostedRoundsAttr)
+				}
+			}
+			full, err := booster.GetModelRaw()
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := truncated.GetModelRaw()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(raw) >= len(full) {
+				t.Errorf("truncated model has %d bytes, the full one %d", len(raw), len(full))
+			}
+		})
+	}
+}
+
+func TestPruneTrees(t *testing.T) {
+	dm, rows := shapTestData(t, 1)
+	defer dm.Free()
+	booster, err := Train(Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"}, dm, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	m, err := booster.TreeModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// prune about half of the trees
+	minGain := (m.Trees[0].TotalGain() + m.Trees[len(m.Trees)-1].TotalGain()) / 2
+	want := m.PruneTrees(minGain)
+	if len(want.Trees) == 0 || len(want.Trees) == len(m.Trees) {
+		t.Fatalf("pruning kept %d of %d trees", len(want.Trees), len(m.Trees))
+	}
+
+	pruned, err := booster.PruneTrees(minGain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pruned.Free()
+	preds, err := pruned.Predict(dm, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		expected, err := want.Predict(row)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeEnough(float64(preds[i]), float64(expected[0])) {
+			t.Fatalf("row %d: pruned booster %v, pruned model %v", i, preds[i], expected[0])
+		}
+	}
+}
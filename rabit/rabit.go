@@ -0,0 +1,175 @@
+// Package rabit wraps the rabit collective communication library used by
+// distributed xgboost training. Workers find each other through a tracker,
+// configured by the DMLC_* environment variables or by arguments to Init.
+//
+// The rabit engine lives in thread local storage, so Init locks the calling goroutine
+// to its thread until Finalize. Call rabit, and xgboost training that relies on it,
+// from that goroutine only.
+package rabit
+
+//#cgo LDFLAGS: -L${SRCDIR}/../lib -lrabit -lstdc++ -lrt -lm -lpthread
+//#cgo CFLAGS: -I ${SRCDIR}/../lib/xgboost/rabit/
+//#include <stdlib.h>
+//#include "c_api.h"
+import "C"
+
+import (
+	"encoding/binary"
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// Op is a reduction operator of Allreduce
+type Op int
+
+// operators, matching rabit::engine::mpi::OpType
+const (
+	Max   Op = 0
+	Min   Op = 1
+	Sum   Op = 2
+	BitOR Op = 3
+)
+
+// data types, matching rabit::engine::mpi::DataType
+const (
+	typeInt    = 2
+	typeFloat  = 6
+	typeDouble = 7
+)
+
+// Init initialize rabit. args are "name=value" settings such as
+// "rabit_tracker_uri=127.0.0.1"; without them rabit reads the DMLC_* environment.
+func Init(args ...string) {
+	runtime.LockOSThread()
+	argv := make([]*C.char, len(args)+1)
+	for i, v := range args {
+		argv[i] = C.CString(v)
+	}
+	defer func() {
+		for _, v := range argv[:len(args)] {
+			C.free(unsafe.Pointer(v))
+		}
+	}()
+	C.RabitInit(C.int(len(args)), (**C.char)(unsafe.Pointer(&argv[0])))
+}
+
+// Finalize shut rabit down, call it once the job is done
+func Finalize() {
+	C.RabitFinalize()
+	runtime.UnlockOSThread()
+}
+
+// Rank get rank of the current worker
+func Rank() int {
+	return int(C.RabitGetRank())
+}
+
+// WorldSize get total number of workers
+func WorldSize() int {
+	return int(C.RabitGetWorldSize())
+}
+
+// IsDistributed report whether rabit runs with a tracker
+func IsDistributed() bool {
+	return C.RabitIsDistributed() != 0
+}
+
+// TrackerPrint print msg on the tracker
+func TrackerPrint(msg string) {
+	msgC := C.CString(msg)
+	defer C.free(unsafe.Pointer(msgC))
+	C.RabitTrackerPrint(msgC)
+}
+
+// ProcessorName get the host name of the current worker
+func ProcessorName() string {
+	buf := make([]byte, 256)
+	var outLen C.rbt_ulong
+	C.RabitGetProcessorName((*C.char)(unsafe.Pointer(&buf[0])), &outLen, C.rbt_ulong(len(buf)))
+	return string(buf[:outLen])
+}
+
+// Broadcast send data of root to every worker and return it. data is ignored on other workers.
+func Broadcast(data []byte, root int) []byte {
+	size := make([]byte, 8)
+	if Rank() == root {
+		binary.LittleEndian.PutUint64(size, uint64(len(data)))
+	}
+	C.RabitBroadcast(unsafe.Pointer(&size[0]), C.rbt_ulong(len(size)), C.int(root))
+
+	n := binary.LittleEndian.Uint64(size)
+	out := make([]byte, n)
+	if Rank() == root {
+		copy(out, data)
+	}
+	if n > 0 {
+		C.RabitBroadcast(unsafe.Pointer(&out[0]), C.rbt_ulong(n), C.int(root))
+	}
+	return out
+}
+
+// AllreduceFloat32 reduce buf in place over every worker
+func AllreduceFloat32(buf []float32, op Op) error {
+	if op == BitOR {
+		return errors.New("bitwise or is not defined on float32")
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return allreduce(unsafe.Pointer(&buf[0]), len(buf), typeFloat, op)
+}
+
+// AllreduceFloat64 reduce buf in place over every worker
+func AllreduceFloat64(buf []float64, op Op) error {
+	if op == BitOR {
+		return errors.New("bitwise or is not defined on float64")
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return allreduce(unsafe.Pointer(&buf[0]), len(buf), typeDouble, op)
+}
+
+// AllreduceInt32 reduce buf in place over every worker
+func AllreduceInt32(buf []int32, op Op) error {
+	if len(buf) == 0 {
+		return nil
+	}
+	return allreduce(unsafe.Pointer(&buf[0]), len(buf), typeInt, op)
+}
+
+func allreduce(buf unsafe.Pointer, count int, dtype int, op Op) error {
+	if op < Max || op > BitOR {
+		return errors.New("unknown reduce operator")
+	}
+	C.RabitAllreduce(buf, C.size_t(count), C.int(dtype), C.int(op), nil, nil)
+	return nil
+}
+
+// CheckPoint store global, the model state shared by every worker, as a new checkpoint
+func CheckPoint(global []byte) {
+	var ptr *C.char
+	if len(global) > 0 {
+		ptr = (*C.char)(unsafe.Pointer(&global[0]))
+	}
+	C.RabitCheckPoint(ptr, C.rbt_ulong(len(global)), nil, 0)
+}
+
+// LoadCheckPoint get the latest checkpoint and its version; version 0 means there is none
+func LoadCheckPoint() (version int, global []byte) {
+	var (
+		outPtr *C.char
+		outLen C.rbt_ulong
+	)
+	version = int(C.RabitLoadCheckPoint(&outPtr, &outLen, nil, nil))
+	if version == 0 {
+		return 0, nil
+	}
+	return version, C.GoBytes(unsafe.Pointer(outPtr), C.int(outLen))
+}
+
+// VersionNumber get the number of checkpoints taken so far
+func VersionNumber() int {
+	return int(C.RabitVersionNumber())
+}
@@ -0,0 +1,264 @@
+package rabit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/tracker"
+)
+
+// TestMain run the worker side of the multi worker tests when started as a worker process
+func TestMain(m *testing.M) {
+	var run func() error
+	switch os.Getenv("RABIT_TEST_WORKER") {
+	case "1":
+		run = runWorker
+	case "recover":
+		run = runRecoverWorker
+	}
+	if run != nil {
+		if err := run(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func runWorker() error {
+	Init()
+	defer Finalize()
+
+	rank, world := Rank(), WorldSize()
+	if !IsDistributed() {
+		return fmt.Errorf("worker %d is not distributed", rank)
+	}
+
+	sum := []float64{float64(rank), 1}
+	if err := AllreduceFloat64(sum, Sum); err != nil {
+		return err
+	}
+	if want := float64(world * (world - 1) / 2); sum[0] != want || sum[1] != float64(world) {
+		return fmt.Errorf("worker %d: sum allreduce gave %v", rank, sum)
+	}
+
+	max := []float32{float32(rank)}
+	if err := AllreduceFloat32(max, Max); err != nil {
+		return err
+	}
+	if max[0] != float32(world-1) {
+		return fmt.Errorf("worker %d: max allreduce gave %v", rank, max)
+	}
+
+	bits := []int32{1 << uint(rank), int32(rank)}
+	if err := AllreduceInt32(bits, BitOR); err != nil {
+		return err
+	}
+	if bits[0] != int32(1<<uint(world))-1 {
+		return fmt.Errorf("worker %d: bitor allreduce gave %v", rank, bits)
+	}
+	min := bits[1:]
+	min[0] = int32(rank)
+	if err := AllreduceInt32(min, Min); err != nil {
+		return err
+	}
+	if min[0] != 0 {
+		return fmt.Errorf("worker %d: min allreduce gave %v", rank, min)
+	}
+
+	var payload []byte
+	if rank == 1 {
+		payload = []byte("hello from rank 1")
+	}
+	if got := Broadcast(payload, 1); string(got) != "hello from rank 1" {
+		return fmt.Errorf("worker %d: broadcast gave %q", rank, got)
+	}
+	TrackerPrint(fmt.Sprintf("worker %d done\n", rank))
+	return nil
+}
+
+// recoverRounds is the number of rounds run by runRecoverWorker
+const recoverRounds = 5
+
+// runRecoverWorker sum the ranks over recoverRounds rounds, checkpointing the running
+// total every round. The first attempt of task 1 dies after round 2.
+func runRecoverWorker() error {
+	Init()
+	defer Finalize()
+
+	var total float64
+	version, global := LoadCheckPoint()
+	if version > 0 {
+		total = math.Float64frombits(binary.LittleEndian.Uint64(global))
+		TrackerPrint(fmt.Sprintf("rank %d resumed at version %d\n", Rank(), version))
+	}
+	for round := version; round < recoverRounds; round++ {
+		buf := []float64{float64(Rank() + 1)}
+		if err := AllreduceFloat64(buf, Sum); err != nil {
+			return err
+		}
+		total += buf[0]
+		state := make([]byte, 8)
+		binary.LittleEndian.PutUint64(state, math.Float64bits(total))
+		CheckPoint(state)
+
+		if round == 2 && os.Getenv("DMLC_TASK_ID") == "1" && os.Getenv("DMLC_NUM_ATTEMPT") == "0" {
+			self, _ := os.FindProcess(os.Getpid())
+			self.Kill()
+		}
+	}
+
+	world := WorldSize()
+	if want := float64(recoverRounds * world * (world + 1) / 2); total != want {
+		return fmt.Errorf("rank %d: total %v, expected %v", Rank(), total, want)
+	}
+	return nil
+}
+
+// startWorker run the test binary as rabit worker task of tr
+func startWorker(t *testing.T, mode string, task int, attempt int, tr *tracker.Tracker) *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), tr.WorkerEnv()...)
+	cmd.Env = append(cmd.Env,
+		"RABIT_TEST_WORKER="+mode,
+		"DMLC_TASK_ID="+strconv.Itoa(task),
+		"DMLC_NUM_ATTEMPT="+strconv.Itoa(attempt),
+		"DMLC_ROLE=worker",
+	)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+// startWorkers run n copies of the test binary as rabit workers of tr
+func startWorkers(t *testing.T, n int, tr *tracker.Tracker) []*exec.Cmd {
+	cmds := make([]*exec.Cmd, n)
+	for i := range cmds {
+		cmds[i] = startWorker(t, "1", i, 0, tr)
+	}
+	return cmds
+}
+
+func TestMultiWorker(t *testing.T) {
+	tr, err := tracker.New("127.0.0.1:0", 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var printed []string
+	tr.Print = func(msg string) {
+		mu.Lock()
+		printed = append(printed, msg)
+		mu.Unlock()
+	}
+	done := make(chan error, 1)
+	go func() { done <- tr.Run() }()
+
+	for i, cmd := range startWorkers(t, 4, tr) {
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("worker %d: %v\n%s", i, err, cmd.Stdout)
+		}
+	}
+	if t.Failed() {
+		tr.Close()
+		return
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(printed) != 4 {
+		t.Errorf("expected a message from every worker, got %q", printed)
+	}
+}
+
+func TestWorkerRecovery(t *testing.T) {
+	tr, err := tracker.New("127.0.0.1:0", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var printed []string
+	tr.Print = func(msg string) {
+		mu.Lock()
+		printed = append(printed, msg)
+		mu.Unlock()
+	}
+	done := make(chan error, 1)
+	go func() { done <- tr.Run() }()
+	defer tr.Close()
+
+	cmds := make([]*exec.Cmd, 3)
+	for i := range cmds {
+		cmds[i] = startWorker(t, "recover", i, 0, tr)
+	}
+	if err := cmds[1].Wait(); err == nil {
+		t.Fatal("expected worker 1 to be killed")
+	}
+	cmds[1] = startWorker(t, "recover", 1, 1, tr)
+
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Errorf("worker %d: %v\n%s", i, err, cmd.Stdout)
+		}
+	}
+	if t.Failed() {
+		return
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(printed) != 1 || !strings.Contains(printed[0], "resumed at version 3") {
+		t.Errorf("expected the restarted worker to resume at version 3, got %q", printed)
+	}
+}
+
+func TestSingleWorker(t *testing.T) {
+	Init()
+	defer Finalize()
+
+	if Rank() != 0 || WorldSize() != 1 || IsDistributed() {
+		t.Fatalf("unexpected single worker setup: rank %d, world size %d", Rank(), WorldSize())
+	}
+	if ProcessorName() == "" {
+		t.Error("empty processor name")
+	}
+
+	buf := []float32{1.5, -2}
+	if err := AllreduceFloat32(buf, Sum); err != nil {
+		t.Error(err)
+	}
+	if buf[0] != 1.5 || buf[1] != -2 {
+		t.Errorf("allreduce changed single worker data: %v", buf)
+	}
+	if err := AllreduceFloat32(buf, BitOR); err == nil {
+		t.Error("expected error for bitwise or on floats")
+	}
+	if got := Broadcast([]byte("abc"), 0); string(got) != "abc" {
+		t.Errorf("broadcast gave %q", got)
+	}
+
+	if version, _ := LoadCheckPoint(); version != 0 {
+		t.Errorf("expected no checkpoint, got version %d", version)
+	}
+	CheckPoint([]byte("model"))
+	if VersionNumber() != 1 {
+		t.Errorf("expected version 1 after a checkpoint, got %d", VersionNumber())
+	}
+}
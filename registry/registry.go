@@ -0,0 +1,344 @@
+// Package registry keeps versions of models in a Storage, with metadata describing how
+// every version was trained. Versions of a model are numbered from 1, and aliases such
+// as "production" point to one version and remember the versions they pointed to
+// before, so that a promotion can be rolled back.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+// metadataAttr is the booster attribute holding the registry metadata of a model
+const metadataAttr = "registry_metadata"
+
+// Metadata describe a version of a model
+type Metadata struct {
+	Name         string             `json:"name"`
+	Version      int                `json:"version"`
+	Params       xgboost.Params     `json:"params,omitempty"`
+	Metrics      map[string]float64 `json:"metrics,omitempty"`
+	FeatureNames []string           `json:"feature_names,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	// Hash is the Fingerprint of the model before its metadata was recorded
+	Hash string `json:"hash"`
+}
+
+// Registry keep versioned models in a Storage. A Registry is safe for concurrent use,
+// but only one Registry may write to a Storage at a time.
+type Registry struct {
+	storage Storage
+	mu      sync.Mutex
+}
+
+// New create a registry over storage
+func New(storage Storage) *Registry {
+	return &Registry{storage: storage}
+}
+
+func modelKey(name string, version int) string {
+	return fmt.Sprintf("%s/v%06d.model", name, version)
+}
+
+func metadataKey(name string, version int) string {
+	return fmt.Sprintf("%s/v%06d.json", name, version)
+}
+
+func aliasesKey(name string) string {
+	return name + "/aliases.json"
+}
+
+func checkName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("registry: invalid model name %q", name)
+	}
+	return nil
+}
+
+// Register store booster as the next version of model name. Name, Version, CreatedAt
+// and Hash of meta are filled in by the registry, the other fields are kept as given.
+// The metadata is also recorded as an attribute of booster, so it travels with the
+// model file.
+func (r *Registry) Register(name string, booster *xgboost.Booster, meta Metadata) (*Metadata, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	hash, err := booster.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, err := r.versions(name)
+	if err != nil {
+		return nil, err
+	}
+	meta.Name = name
+	meta.Version = 1
+	if len(versions) > 0 {
+		meta.Version = versions[len(versions)-1] + 1
+	}
+	meta.CreatedAt = time.Now().UTC()
+	meta.Hash = hash
+
+	encoded, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := booster.SetAttr(metadataAttr, string(encoded)); err != nil {
+		return nil, err
+	}
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return nil, err
+	}
+	// the model goes first, a version is only listed once its metadata is written
+	if err := r.storage.Put(modelKey(name, meta.Version), raw); err != nil {
+		return nil, err
+	}
+	if err := r.storage.Put(metadataKey(name, meta.Version), encoded); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// versions list the versions of model name in increasing order
+func (r *Registry) versions(name string) ([]int, error) {
+	keys, err := r.storage.List(name + "/")
+	if err != nil {
+		return nil, err
+	}
+	var versions []int
+	for _, key := range keys {
+		base := path.Base(key)
+		if !strings.HasPrefix(base, "v") || !strings.HasSuffix(base, ".json") {
+			continue
+		}
+		version, err := strconv.Atoi(strings.TrimSuffix(base[1:], ".json"))
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// Models list the names of the registered models
+func (r *Registry) Models() ([]string, error) {
+	keys, err := r.storage.List("")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, key := range keys {
+		i := strings.Index(key, "/")
+		if i <= 0 || !strings.HasSuffix(key, ".json") {
+			continue
+		}
+		if name := key[:i]; len(names) == 0 || names[len(names)-1] != name {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// List get the metadata of every version of model name, oldest first
+func (r *Registry) List(name string) ([]*Metadata, error) {
+	versions, err := r.versions(name)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]*Metadata, 0, len(versions))
+	for _, version := range versions {
+		meta, err := r.Metadata(name, version)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, meta)
+	}
+	return list, nil
+}
+
+// Latest get the newest version of model name
+func (r *Registry) Latest(name string) (int, error) {
+	versions, err := r.versions(name)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, fmt.Errorf("registry: model %s: %w", name, ErrNotFound)
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Metadata get the metadata of a version of model name
+func (r *Registry) Metadata(name string, version int) (*Metadata, error) {
+	data, err := r.storage.Get(metadataKey(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("registry: model %s version %d: %w", name, version, err)
+	}
+	meta := &Metadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, fmt.Errorf("registry: model %s version %d: %v", name, version, err)
+	}
+	return meta, nil
+}
+
+// Raw get the saved model of a version of model name
+func (r *Registry) Raw(name string, version int) ([]byte, error) {
+	raw, err := r.storage.Get(modelKey(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("registry: model %s version %d: %w", name, version, err)
+	}
+	return raw, nil
+}
+
+// Load load a version of model name into a new booster
+func (r *Registry) Load(name string, version int) (*xgboost.Booster, *Metadata, error) {
+	meta, err := r.Metadata(name, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := r.Raw(name, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := booster.LoadModelFromBuffer(raw); err != nil {
+		booster.Free()
+		return nil, nil, err
+	}
+	return booster, meta, nil
+}
+
+// LoadAlias load the version of model name that alias points to
+func (r *Registry) LoadAlias(name string, alias string) (*xgboost.Booster, *Metadata, error) {
+	version, err := r.Alias(name, alias)
+	if err != nil {
+		return nil, nil, err
+	}
+	return r.Load(name, version)
+}
+
+// aliases get the history of every alias of model name, the current version last
+func (r *Registry) aliases(name string) (map[string][]int, error) {
+	aliases := map[string][]int{}
+	data, err := r.storage.Get(aliasesKey(name))
+	if errors.Is(err, ErrNotFound) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("registry: aliases of %s: %v", name, err)
+	}
+	return aliases, nil
+}
+
+func (r *Registry) saveAliases(name string, aliases map[string][]int) error {
+	encoded, err := json.Marshal(aliases)
+	if err != nil {
+		return err
+	}
+	return r.storage.Put(aliasesKey(name), encoded)
+}
+
+// Aliases get the version every alias of model name points to
+func (r *Registry) Aliases(name string) (map[string]int, error) {
+	aliases, err := r.aliases(name)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]int{}
+	for alias, history := range aliases {
+		if len(history) > 0 {
+			current[alias] = history[len(history)-1]
+		}
+	}
+	return current, nil
+}
+
+// Alias get the version alias of model name points to
+func (r *Registry) Alias(name string, alias string) (int, error) {
+	current, err := r.Aliases(name)
+	if err != nil {
+		return 0, err
+	}
+	version, ok := current[alias]
+	if !ok {
+		return 0, fmt.Errorf("registry: alias %s of model %s: %w", alias, name, ErrNotFound)
+	}
+	return version, nil
+}
+
+// Promote point alias of model name to version
+func (r *Registry) Promote(name string, version int, alias string) error {
+	if alias == "" {
+		return errors.New("registry: empty alias")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.Metadata(name, version); err != nil {
+		return err
+	}
+	aliases, err := r.aliases(name)
+	if err != nil {
+		return err
+	}
+	history := aliases[alias]
+	if len(history) > 0 && history[len(history)-1] == version {
+		return nil
+	}
+	aliases[alias] = append(history, version)
+	return r.saveAliases(name, aliases)
+}
+
+// Rollback point alias of model name back to the version it pointed to before the
+// last promotion, and return that version
+func (r *Registry) Rollback(name string, alias string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	aliases, err := r.aliases(name)
+	if err != nil {
+		return 0, err
+	}
+	history := aliases[alias]
+	if len(history) < 2 {
+		return 0, fmt.Errorf("registry: alias %s of model %s has no earlier version", alias, name)
+	}
+	history = history[:len(history)-1]
+	aliases[alias] = history
+	if err := r.saveAliases(name, aliases); err != nil {
+		return 0, err
+	}
+	return history[len(history)-1], nil
+}
+
+// ReadMetadata get the registry metadata recorded in booster, or nil when it was never
+// registered
+func ReadMetadata(booster *xgboost.Booster) (*Metadata, error) {
+	attr, err := booster.GetAttr(metadataAttr)
+	if err != nil || attr == "" {
+		return nil, err
+	}
+	meta := &Metadata{}
+	if err := json.Unmarshal([]byte(attr), meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// trainBooster train a small regression model for rounds rounds
+func trainBooster(t *testing.T, rounds int) *xgboost.Booster {
+	data := make(model.Matrix, 30)
+	labels := make([]float32, len(data))
+	for i := range data {
+		data[i] = []float32{float32(i), float32(i % 5)}
+		labels[i] = float32(i + i%5)
+	}
+	dtrain, err := xgboost.DMatrixCreateFromMat(data, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dtrain.Free()
+	if err := dtrain.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	booster, err := xgboost.Train(xgboost.Params{"objective": "reg:linear", "max_depth": "2", "silent": "1"}, dtrain, rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return booster
+}
+
+func testRegistry(t *testing.T, r *Registry) {
+	if _, err := r.Latest("churn"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown model, got %v", err)
+	}
+	if _, err := r.Register("bad/name", nil, Metadata{}); err == nil {
+		t.Error("expected an error for a name with a slash")
+	}
+
+	var registered []*Metadata
+	for rounds := 1; rounds <= 3; rounds++ {
+		booster := trainBooster(t, rounds)
+		hash, err := booster.Fingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta, err := r.Register("churn", booster, Metadata{
+			Params:       xgboost.Params{"max_depth": "2"},
+			Metrics:      map[string]float64{"rmse": float64(4 - rounds)},
+			FeatureNames: []string{"age", "plan"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Name != "churn" || meta.Version != rounds || meta.Hash != hash || meta.CreatedAt.IsZero() {
+			t.Errorf("unexpected metadata %+v", meta)
+		}
+		recorded, err := ReadMetadata(booster)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recorded.Version != rounds || recorded.Hash != hash {
+			t.Errorf("booster records %+v, registered %+v", recorded, meta)
+		}
+		booster.Free()
+		registered = append(registered, meta)
+	}
+	fraud := trainBooster(t, 1)
+	defer fraud.Free()
+	if _, err := r.Register("fraud", fraud, Metadata{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if names, err := r.Models(); err != nil || !reflect.DeepEqual(names, []string{"churn", "fraud"}) {
+		t.Errorf("unexpected models %v %v", names, err)
+	}
+	list, err := r.List("churn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 || list[2].Metrics["rmse"] != 1 || !reflect.DeepEqual(list[0].FeatureNames, []string{"age", "plan"}) {
+		t.Errorf("unexpected versions %+v", list)
+	}
+	if latest, err := r.Latest("churn"); err != nil || latest != 3 {
+		t.Errorf("latest version %d %v, expected 3", latest, err)
+	}
+
+	booster, meta, err := r.Load("churn", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	if meta.Hash != registered[1].Hash {
+		t.Errorf("loaded version 2 with hash %s, registered %s", meta.Hash, registered[1].Hash)
+	}
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := r.Raw("churn", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw, stored) {
+		t.Error("loaded model differs from the stored one")
+	}
+	if _, _, err := r.Load("churn", 9); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing version, got %v", err)
+	}
+
+	// promote 1, then 3, then roll back to 1
+	if _, err := r.Alias("churn", "production"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing alias, got %v", err)
+	}
+	if err := r.Promote("churn", 9, "production"); err == nil {
+		t.Error("expected an error promoting a missing version")
+	}
+	for _, version := range []int{1, 3} {
+		if err := r.Promote("churn", version, "production"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Promote("churn", 2, "staging"); err != nil {
+		t.Fatal(err)
+	}
+	if aliases, err := r.Aliases("churn"); err != nil || !reflect.DeepEqual(aliases, map[string]int{"production": 3, "staging": 2}) {
+		t.Errorf("unexpected aliases %v %v", aliases, err)
+	}
+	production, meta, err := r.LoadAlias("churn", "production")
+	if err != nil {
+		t.Fatal(err)
+	}
+	production.Free()
+	if meta.Version != 3 {
+		t.Errorf("production loads version %d, expected 3", meta.Version)
+	}
+	if version, err := r.Rollback("churn", "production"); err != nil || version != 1 {
+		t.Errorf("rolled back to %d %v, expected 1", version, err)
+	}
+	if version, err := r.Alias("churn", "production"); err != nil || version != 1 {
+		t.Errorf("production is %d %v after rollback, expected 1", version, err)
+	}
+	if _, err := r.Rollback("churn", "production"); err == nil {
+		t.Error("expected an error rolling back past the first promotion")
+	}
+}
+
+func TestRegistryMemory(t *testing.T) {
+	testRegistry(t, New(NewMemoryStorage()))
+}
+
+func TestRegistryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testRegistry(t, New(&FileStorage{Dir: dir}))
+
+	// a second registry over the same directory sees the same versions and aliases
+	r := New(&FileStorage{Dir: dir})
+	if latest, err := r.Latest("churn"); err != nil || latest != 3 {
+		t.Errorf("latest version %d %v, expected 3", latest, err)
+	}
+	if version, err := r.Alias("churn", "production"); err != nil || version != 1 {
+		t.Errorf("production is %d %v, expected 1", version, err)
+	}
+}
@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func testStorage(t *testing.T, s Storage) {
+	if _, err := s.Get("a/v1"); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound for a missing key, got %v", err)
+	}
+	if keys, err := s.List(""); err != nil || len(keys) != 0 {
+		t.Fatalf("expected no keys in empty storage, got %v %v", keys, err)
+	}
+	for key, value := range map[string]string{"a/v1": "one", "a/v2": "two", "ab/v1": "other", "b/v1": "three"} {
+		if err := s.Put(key, []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Put("a/v1", []byte("replaced")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := s.Get("a/v1"); err != nil || string(data) != "replaced" {
+		t.Errorf("got %q %v, expected the replaced value", data, err)
+	}
+	keys, err := s.List("a/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a/v1", "a/v2"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if err := s.Delete("a/v1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a/v1"); err != nil {
+		t.Errorf("deleting a missing key: %v", err)
+	}
+	keys, err = s.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(keys, []string{"a/v2", "ab/v1", "b/v1"}) {
+		t.Errorf("unexpected keys after delete %v", keys)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, NewMemoryStorage())
+}
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	testStorage(t, &FileStorage{Dir: dir + "/models"})
+}
@@ -0,0 +1,84 @@
+package xgboost
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// LibraryVersion is the version of the linked libxgboost, recorded in the attributes of
+// reproducible models. The C API has no way to query it, so set it at build time with
+// -ldflags "-X github.com/liuhaoXD/xgboost-go.LibraryVersion=0.82".
+var LibraryVersion = "unknown"
+
+const (
+	trainParamsAttr    = "train_params"
+	libraryVersionAttr = "xgboost_version"
+)
+
+// ReproducibleParams return a copy of params with seed and nthread pinned, so that
+// training with subsampling gives the same model on every run and every machine.
+// nthread below 1 is taken as 1.
+func ReproducibleParams(params Params, seed int, nthread int) Params {
+	if nthread < 1 {
+		nthread = 1
+	}
+	pinned := Params{}
+	for k, v := range params {
+		pinned[k] = v
+	}
+	pinned["seed"] = strconv.Itoa(seed)
+	pinned["nthread"] = strconv.Itoa(nthread)
+	return pinned
+}
+
+// TrainReproducible train like Train with params pinned by ReproducibleParams. The
+// pinned params and LibraryVersion are recorded as booster attributes.
+func TrainReproducible(params Params, dtrain *DMatrix, rounds int, seed int, nthread int) (*Booster, error) {
+	pinned := ReproducibleParams(params, seed, nthread)
+	booster, err := Train(pinned, dtrain, rounds)
+	if err != nil {
+		return nil, err
+	}
+	if err := booster.recordTraining(pinned); err != nil {
+		booster.Free()
+		return nil, err
+	}
+	return booster, nil
+}
+
+func (booster *Booster) recordTraining(params Params) error {
+	// json.Marshal sorts map keys, so equal params give equal attributes
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if err := booster.SetAttr(trainParamsAttr, string(encoded)); err != nil {
+		return err
+	}
+	return booster.SetAttr(libraryVersionAttr, LibraryVersion)
+}
+
+// TrainParams get the params recorded by TrainReproducible, or nil when there are none
+func (booster *Booster) TrainParams() (Params, error) {
+	attr, err := booster.GetAttr(trainParamsAttr)
+	if err != nil || attr == "" {
+		return nil, err
+	}
+	var params Params
+	if err := json.Unmarshal([]byte(attr), &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// Fingerprint get the hex SHA-256 of the raw model, equal for identical models
+func (booster *Booster) Fingerprint() (string, error) {
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,58 @@
+package xgboost
+
+import "testing"
+
+func TestTrainReproducible(t *testing.T) {
+	dtrain := trainTestMatrix(t)
+	defer dtrain.Free()
+
+	params := Params{
+		"objective":        "reg:linear",
+		"max_depth":        "4",
+		"eta":              "0.1",
+		"subsample":        "0.5",
+		"colsample_bytree": "0.7",
+		"silent":           "1",
+	}
+
+	fingerprint := func(seed int, nthread int) string {
+		booster, err := TrainReproducible(params, dtrain, 30, seed, nthread)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer booster.Free()
+		fp, err := booster.Fingerprint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	first := fingerprint(42, 2)
+	if second := fingerprint(42, 2); second != first {
+		t.Errorf("same seed gave different models: %s != %s", first, second)
+	}
+	if other := fingerprint(43, 2); other == first {
+		t.Error("different seeds gave identical models")
+	}
+
+	booster, err := TrainReproducible(params, dtrain, 1, 42, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	recorded, err := booster.TrainParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded["seed"] != "42" || recorded["nthread"] != "1" || recorded["subsample"] != "0.5" {
+		t.Errorf("unexpected recorded params %v", recorded)
+	}
+	version, err := booster.GetAttr(libraryVersionAttr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LibraryVersion {
+		t.Errorf("recorded version %q, expected %q", version, LibraryVersion)
+	}
+}
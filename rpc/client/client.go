@@ -0,0 +1,155 @@
+// Package client is a Go client of the gRPC Predictor service. It does not depend on
+// libxgboost, so it can be used by programs built without cgo.
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/liuhaoXD/xgboost-go/rpc/predictpb"
+)
+
+// Options select what a prediction computes
+type Options = predictpb.PredictOptions
+
+// SparseRow is a row of features given by index
+type SparseRow struct {
+	Indices []uint32
+	Values  []float32
+}
+
+// Client call a Predictor service
+type Client struct {
+	conn *grpc.ClientConn
+	pb   predictpb.PredictorClient
+}
+
+// Dial create a client of the Predictor service at target
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, pb: predictpb.NewPredictorClient(conn)}, nil
+}
+
+// New create a client over an existing connection, which is not closed by Close
+func New(cc grpc.ClientConnInterface) *Client {
+	return &Client{pb: predictpb.NewPredictorClient(cc)}
+}
+
+// Close close the connection opened by Dial
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// PredictDense predict dense rows with model, NaN features are missing.
+// It returns the predictions of every row
+func (c *Client) PredictDense(ctx context.Context, model string, rows [][]float32, opts *Options) ([][]float32, error) {
+	resp, err := c.pb.Predict(ctx, DenseRequest(model, rows, opts))
+	if err != nil {
+		return nil, err
+	}
+	return predictions(resp, len(rows))
+}
+
+// PredictSparse predict sparse rows with model
+func (c *Client) PredictSparse(ctx context.Context, model string, rows []SparseRow, opts *Options) ([][]float32, error) {
+	resp, err := c.pb.Predict(ctx, SparseRequest(model, rows, opts))
+	if err != nil {
+		return nil, err
+	}
+	return predictions(resp, len(rows))
+}
+
+// Contributions compute the feature contributions of dense rows, the last value of a
+// row is the bias
+func (c *Client) Contributions(ctx context.Context, model string, rows [][]float32) ([][]float32, error) {
+	resp, err := c.pb.PredictContributions(ctx, DenseRequest(model, rows, nil))
+	if err != nil {
+		return nil, err
+	}
+	return predictions(resp, len(rows))
+}
+
+// PredictBatch send several requests in one call, the responses are in request order
+func (c *Client) PredictBatch(ctx context.Context, reqs []*predictpb.PredictRequest) ([]*predictpb.PredictResponse, error) {
+	resp, err := c.pb.PredictBatch(ctx, &predictpb.PredictBatchRequest{Requests: reqs})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.GetResponses()) != len(reqs) {
+		return nil, fmt.Errorf("got %d responses for %d requests", len(resp.GetResponses()), len(reqs))
+	}
+	return resp.GetResponses(), nil
+}
+
+// ModelInfo get the description of model
+func (c *Client) ModelInfo(ctx context.Context, model string) (*predictpb.ModelInfo, error) {
+	return c.pb.GetModelInfo(ctx, &predictpb.GetModelInfoRequest{Model: model})
+}
+
+// Stream is a bidirectional prediction stream, the server answers requests in order
+type Stream struct {
+	stream grpc.BidiStreamingClient[predictpb.PredictRequest, predictpb.PredictResponse]
+}
+
+// Stream open a prediction stream, it is closed when ctx is done
+func (c *Client) Stream(ctx context.Context) (*Stream, error) {
+	stream, err := c.pb.PredictStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{stream: stream}, nil
+}
+
+// Send send a request on the stream
+func (s *Stream) Send(req *predictpb.PredictRequest) error {
+	return s.stream.Send(req)
+}
+
+// Recv receive the response of the oldest unanswered request
+func (s *Stream) Recv() (*predictpb.PredictResponse, error) {
+	return s.stream.Recv()
+}
+
+// CloseSend tell the server no more requests are sent
+func (s *Stream) CloseSend() error {
+	return s.stream.CloseSend()
+}
+
+// DenseRequest build a request predicting dense rows
+func DenseRequest(model string, rows [][]float32, opts *Options) *predictpb.PredictRequest {
+	req := &predictpb.PredictRequest{Model: model, Options: opts, Rows: make([]*predictpb.Row, len(rows))}
+	for i, row := range rows {
+		req.Rows[i] = &predictpb.Row{Features: &predictpb.Row_Dense{Dense: &predictpb.DenseRow{Values: row}}}
+	}
+	return req
+}
+
+// SparseRequest build a request predicting sparse rows
+func SparseRequest(model string, rows []SparseRow, opts *Options) *predictpb.PredictRequest {
+	req := &predictpb.PredictRequest{Model: model, Options: opts, Rows: make([]*predictpb.Row, len(rows))}
+	for i, row := range rows {
+		req.Rows[i] = &predictpb.Row{Features: &predictpb.Row_Sparse{
+			Sparse: &predictpb.SparseRow{Indices: row.Indices, Values: row.Values},
+		}}
+	}
+	return req
+}
+
+func predictions(resp *predictpb.PredictResponse, rows int) ([][]float32, error) {
+	if len(resp.GetPredictions()) != rows {
+		return nil, fmt.Errorf("got %d predictions for %d rows", len(resp.GetPredictions()), rows)
+	}
+	out := make([][]float32, rows)
+	for i, p := range resp.GetPredictions() {
+		out[i] = p.GetValues()
+	}
+	return out, nil
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/liuhaoXD/xgboost-go/rpc/predictpb"
+)
+
+// sumServer predict the sum of the features of every row
+type sumServer struct {
+	predictpb.UnimplementedPredictorServer
+}
+
+func (sumServer) Predict(ctx context.Context, req *predictpb.PredictRequest) (*predictpb.PredictResponse, error) {
+	if req.Model != "sum" {
+		return nil, status.Error(codes.NotFound, "model not found")
+	}
+	resp := &predictpb.PredictResponse{Model: req.Model, ModelVersion: 1}
+	for _, row := range req.Rows {
+		var sum float32
+		if dense := row.GetDense(); dense != nil {
+			for _, v := range dense.Values {
+				sum += v
+			}
+		} else {
+			for _, v := range row.GetSparse().GetValues() {
+				sum += v
+			}
+		}
+		if req.GetOptions().GetOutputMargin() {
+			sum = -sum
+		}
+		resp.Predictions = append(resp.Predictions, &predictpb.Prediction{Values: []float32{sum}})
+	}
+	return resp, nil
+}
+
+func (s sumServer) PredictStream(stream predictpb.Predictor_PredictStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := s.Predict(stream.Context(), req)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (sumServer) GetModelInfo(ctx context.Context, req *predictpb.GetModelInfoRequest) (*predictpb.ModelInfo, error) {
+	return &predictpb.ModelInfo{Name: req.Model, Version: 1, NumTrees: 3}, nil
+}
+
+func TestClient(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	predictpb.RegisterPredictorServer(g, sumServer{})
+	go g.Serve(lis)
+	defer g.Stop()
+
+	c, err := Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx := context.Background()
+
+	dense, err := c.PredictDense(ctx, "sum", [][]float32{{1, 2, 3}, {4, 5}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dense) != 2 || dense[0][0] != 6 || dense[1][0] != 9 {
+		t.Errorf("unexpected dense predictions %v", dense)
+	}
+	sparse, err := c.PredictSparse(ctx, "sum", []SparseRow{{Indices: []uint32{4}, Values: []float32{7}}}, &Options{OutputMargin: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sparse) != 1 || sparse[0][0] != -7 {
+		t.Errorf("unexpected sparse predictions %v", sparse)
+	}
+	if _, err := c.PredictDense(ctx, "other", [][]float32{{1}}, nil); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+	if _, err := c.Contributions(ctx, "sum", [][]float32{{1}}); status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented, got %v", err)
+	}
+
+	info, err := c.ModelInfo(ctx, "sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "sum" || info.NumTrees != 3 {
+		t.Errorf("unexpected model info %v", info)
+	}
+
+	stream, err := c.Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := stream.Send(DenseRequest("sum", [][]float32{{float32(i)}}, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stream.CloseSend()
+	for i := 1; i <= 3; i++ {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Predictions[0].Values[0] != float32(i) {
+			t.Errorf("stream response %d out of order: %v", i, resp)
+		}
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected end of stream, got %v", err)
+	}
+}
@@ -0,0 +1,5 @@
+// Package predictpb holds the protocol buffer messages and gRPC service of the
+// prediction service, generated from predict.proto.
+package predictpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative predict.proto
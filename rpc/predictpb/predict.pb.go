@@ -0,0 +1,783 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: predict.proto
+
+package predictpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DenseRow holds every feature of a row, NaN marking a missing value.
+type DenseRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DenseRow) Reset() {
+	*x = DenseRow{}
+	mi := &file_predict_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DenseRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DenseRow) ProtoMessage() {}
+
+func (x *DenseRow) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DenseRow.ProtoReflect.Descriptor instead.
+func (*DenseRow) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DenseRow) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// SparseRow holds the present features of a row.
+type SparseRow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Indices       []uint32               `protobuf:"varint,1,rep,packed,name=indices,proto3" json:"indices,omitempty"`
+	Values        []float32              `protobuf:"fixed32,2,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SparseRow) Reset() {
+	*x = SparseRow{}
+	mi := &file_predict_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SparseRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SparseRow) ProtoMessage() {}
+
+func (x *SparseRow) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SparseRow.ProtoReflect.Descriptor instead.
+func (*SparseRow) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SparseRow) GetIndices() []uint32 {
+	if x != nil {
+		return x.Indices
+	}
+	return nil
+}
+
+func (x *SparseRow) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Row struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Features:
+	//
+	//	*Row_Dense
+	//	*Row_Sparse
+	Features      isRow_Features `protobuf_oneof:"features"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Row) Reset() {
+	*x = Row{}
+	mi := &file_predict_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Row) ProtoMessage() {}
+
+func (x *Row) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Row.ProtoReflect.Descriptor instead.
+func (*Row) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Row) GetFeatures() isRow_Features {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+func (x *Row) GetDense() *DenseRow {
+	if x != nil {
+		if x, ok := x.Features.(*Row_Dense); ok {
+			return x.Dense
+		}
+	}
+	return nil
+}
+
+func (x *Row) GetSparse() *SparseRow {
+	if x != nil {
+		if x, ok := x.Features.(*Row_Sparse); ok {
+			return x.Sparse
+		}
+	}
+	return nil
+}
+
+type isRow_Features interface {
+	isRow_Features()
+}
+
+type Row_Dense struct {
+	Dense *DenseRow `protobuf:"bytes,1,opt,name=dense,proto3,oneof"`
+}
+
+type Row_Sparse struct {
+	Sparse *SparseRow `protobuf:"bytes,2,opt,name=sparse,proto3,oneof"`
+}
+
+func (*Row_Dense) isRow_Features() {}
+
+func (*Row_Sparse) isRow_Features() {}
+
+type PredictOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// output_margin returns the untransformed margin.
+	OutputMargin bool `protobuf:"varint,1,opt,name=output_margin,json=outputMargin,proto3" json:"output_margin,omitempty"`
+	// pred_leaf returns the leaf index of every tree.
+	PredLeaf bool `protobuf:"varint,2,opt,name=pred_leaf,json=predLeaf,proto3" json:"pred_leaf,omitempty"`
+	// ntree_limit limits prediction to the first trees, 0 uses all of them.
+	NtreeLimit    uint32 `protobuf:"varint,3,opt,name=ntree_limit,json=ntreeLimit,proto3" json:"ntree_limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictOptions) Reset() {
+	*x = PredictOptions{}
+	mi := &file_predict_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictOptions) ProtoMessage() {}
+
+func (x *PredictOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictOptions.ProtoReflect.Descriptor instead.
+func (*PredictOptions) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PredictOptions) GetOutputMargin() bool {
+	if x != nil {
+		return x.OutputMargin
+	}
+	return false
+}
+
+func (x *PredictOptions) GetPredLeaf() bool {
+	if x != nil {
+		return x.PredLeaf
+	}
+	return false
+}
+
+func (x *PredictOptions) GetNtreeLimit() uint32 {
+	if x != nil {
+		return x.NtreeLimit
+	}
+	return 0
+}
+
+type PredictRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Rows          []*Row                 `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+	Options       *PredictOptions        `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictRequest) Reset() {
+	*x = PredictRequest{}
+	mi := &file_predict_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictRequest) ProtoMessage() {}
+
+func (x *PredictRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictRequest.ProtoReflect.Descriptor instead.
+func (*PredictRequest) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PredictRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PredictRequest) GetRows() []*Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+func (x *PredictRequest) GetOptions() *PredictOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// Prediction holds the values predicted for one row.
+type Prediction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float32              `protobuf:"fixed32,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Prediction) Reset() {
+	*x = Prediction{}
+	mi := &file_predict_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Prediction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Prediction) ProtoMessage() {}
+
+func (x *Prediction) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Prediction.ProtoReflect.Descriptor instead.
+func (*Prediction) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Prediction) GetValues() []float32 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type PredictResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	ModelVersion  int32                  `protobuf:"varint,2,opt,name=model_version,json=modelVersion,proto3" json:"model_version,omitempty"`
+	Predictions   []*Prediction          `protobuf:"bytes,3,rep,name=predictions,proto3" json:"predictions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictResponse) Reset() {
+	*x = PredictResponse{}
+	mi := &file_predict_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictResponse) ProtoMessage() {}
+
+func (x *PredictResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictResponse.ProtoReflect.Descriptor instead.
+func (*PredictResponse) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PredictResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *PredictResponse) GetModelVersion() int32 {
+	if x != nil {
+		return x.ModelVersion
+	}
+	return 0
+}
+
+func (x *PredictResponse) GetPredictions() []*Prediction {
+	if x != nil {
+		return x.Predictions
+	}
+	return nil
+}
+
+type PredictBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*PredictRequest      `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictBatchRequest) Reset() {
+	*x = PredictBatchRequest{}
+	mi := &file_predict_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictBatchRequest) ProtoMessage() {}
+
+func (x *PredictBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictBatchRequest.ProtoReflect.Descriptor instead.
+func (*PredictBatchRequest) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PredictBatchRequest) GetRequests() []*PredictRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type PredictBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Responses     []*PredictResponse     `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PredictBatchResponse) Reset() {
+	*x = PredictBatchResponse{}
+	mi := &file_predict_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PredictBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PredictBatchResponse) ProtoMessage() {}
+
+func (x *PredictBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PredictBatchResponse.ProtoReflect.Descriptor instead.
+func (*PredictBatchResponse) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PredictBatchResponse) GetResponses() []*PredictResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+type GetModelInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModelInfoRequest) Reset() {
+	*x = GetModelInfoRequest{}
+	mi := &file_predict_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModelInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModelInfoRequest) ProtoMessage() {}
+
+func (x *GetModelInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModelInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetModelInfoRequest) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetModelInfoRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+type ModelInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Path          string                 `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	Version       int32                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	LoadedAtUnix  int64                  `protobuf:"varint,4,opt,name=loaded_at_unix,json=loadedAtUnix,proto3" json:"loaded_at_unix,omitempty"`
+	NumTrees      int32                  `protobuf:"varint,5,opt,name=num_trees,json=numTrees,proto3" json:"num_trees,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,6,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModelInfo) Reset() {
+	*x = ModelInfo{}
+	mi := &file_predict_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModelInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModelInfo) ProtoMessage() {}
+
+func (x *ModelInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_predict_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModelInfo.ProtoReflect.Descriptor instead.
+func (*ModelInfo) Descriptor() ([]byte, []int) {
+	return file_predict_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ModelInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ModelInfo) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetLoadedAtUnix() int64 {
+	if x != nil {
+		return x.LoadedAtUnix
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetNumTrees() int32 {
+	if x != nil {
+		return x.NumTrees
+	}
+	return 0
+}
+
+func (x *ModelInfo) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+var File_predict_proto protoreflect.FileDescriptor
+
+const file_predict_proto_rawDesc = "" +
+	"\n" +
+	"\rpredict.proto\x12\x12xgboost.predict.v1\"\"\n" +
+	"\bDenseRow\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"=\n" +
+	"\tSparseRow\x12\x18\n" +
+	"\aindices\x18\x01 \x03(\rR\aindices\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\x02R\x06values\"\x80\x01\n" +
+	"\x03Row\x124\n" +
+	"\x05dense\x18\x01 \x01(\v2\x1c.xgboost.predict.v1.DenseRowH\x00R\x05dense\x127\n" +
+	"\x06sparse\x18\x02 \x01(\v2\x1d.xgboost.predict.v1.SparseRowH\x00R\x06sparseB\n" +
+	"\n" +
+	"\bfeatures\"s\n" +
+	"\x0ePredictOptions\x12#\n" +
+	"\routput_margin\x18\x01 \x01(\bR\foutputMargin\x12\x1b\n" +
+	"\tpred_leaf\x18\x02 \x01(\bR\bpredLeaf\x12\x1f\n" +
+	"\vntree_limit\x18\x03 \x01(\rR\n" +
+	"ntreeLimit\"\x91\x01\n" +
+	"\x0ePredictRequest\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12+\n" +
+	"\x04rows\x18\x02 \x03(\v2\x17.xgboost.predict.v1.RowR\x04rows\x12<\n" +
+	"\aoptions\x18\x03 \x01(\v2\".xgboost.predict.v1.PredictOptionsR\aoptions\"$\n" +
+	"\n" +
+	"Prediction\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x02R\x06values\"\x8e\x01\n" +
+	"\x0fPredictResponse\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12#\n" +
+	"\rmodel_version\x18\x02 \x01(\x05R\fmodelVersion\x12@\n" +
+	"\vpredictions\x18\x03 \x03(\v2\x1e.xgboost.predict.v1.PredictionR\vpredictions\"U\n" +
+	"\x13PredictBatchRequest\x12>\n" +
+	"\brequests\x18\x01 \x03(\v2\".xgboost.predict.v1.PredictRequestR\brequests\"Y\n" +
+	"\x14PredictBatchResponse\x12A\n" +
+	"\tresponses\x18\x01 \x03(\v2#.xgboost.predict.v1.PredictResponseR\tresponses\"+\n" +
+	"\x13GetModelInfoRequest\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\"\x9e\x02\n" +
+	"\tModelInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04path\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12$\n" +
+	"\x0eloaded_at_unix\x18\x04 \x01(\x03R\floadedAtUnix\x12\x1b\n" +
+	"\tnum_trees\x18\x05 \x01(\x05R\bnumTrees\x12M\n" +
+	"\n" +
+	"attributes\x18\x06 \x03(\v2-.xgboost.predict.v1.ModelInfo.AttributesEntryR\n" +
+	"attributes\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\xd9\x03\n" +
+	"\tPredictor\x12R\n" +
+	"\aPredict\x12\".xgboost.predict.v1.PredictRequest\x1a#.xgboost.predict.v1.PredictResponse\x12a\n" +
+	"\fPredictBatch\x12'.xgboost.predict.v1.PredictBatchRequest\x1a(.xgboost.predict.v1.PredictBatchResponse\x12_\n" +
+	"\x14PredictContributions\x12\".xgboost.predict.v1.PredictRequest\x1a#.xgboost.predict.v1.PredictResponse\x12V\n" +
+	"\fGetModelInfo\x12'.xgboost.predict.v1.GetModelInfoRequest\x1a\x1d.xgboost.predict.v1.ModelInfo\x12\\\n" +
+	"\rPredictStream\x12\".xgboost.predict.v1.PredictRequest\x1a#.xgboost.predict.v1.PredictResponse(\x010\x01B.Z,github.com/liuhaoXD/xgboost-go/rpc/predictpbb\x06proto3"
+
+var (
+	file_predict_proto_rawDescOnce sync.Once
+	file_predict_proto_rawDescData []byte
+)
+
+func file_predict_proto_rawDescGZIP() []byte {
+	file_predict_proto_rawDescOnce.Do(func() {
+		file_predict_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_predict_proto_rawDesc), len(file_predict_proto_rawDesc)))
+	})
+	return file_predict_proto_rawDescData
+}
+
+var file_predict_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_predict_proto_goTypes = []any{
+	(*DenseRow)(nil),             // 0: xgboost.predict.v1.DenseRow
+	(*SparseRow)(nil),            // 1: xgboost.predict.v1.SparseRow
+	(*Row)(nil),                  // 2: xgboost.predict.v1.Row
+	(*PredictOptions)(nil),       // 3: xgboost.predict.v1.PredictOptions
+	(*PredictRequest)(nil),       // 4: xgboost.predict.v1.PredictRequest
+	(*Prediction)(nil),           // 5: xgboost.predict.v1.Prediction
+	(*PredictResponse)(nil),      // 6: xgboost.predict.v1.PredictResponse
+	(*PredictBatchRequest)(nil),  // 7: xgboost.predict.v1.PredictBatchRequest
+	(*PredictBatchResponse)(nil), // 8: xgboost.predict.v1.PredictBatchResponse
+	(*GetModelInfoRequest)(nil),  // 9: xgboost.predict.v1.GetModelInfoRequest
+	(*ModelInfo)(nil),            // 10: xgboost.predict.v1.ModelInfo
+	nil,                          // 11: xgboost.predict.v1.ModelInfo.AttributesEntry
+}
+var file_predict_proto_depIdxs = []int32{
+	0,  // 0: xgboost.predict.v1.Row.dense:type_name -> xgboost.predict.v1.DenseRow
+	1,  // 1: xgboost.predict.v1.Row.sparse:type_name -> xgboost.predict.v1.SparseRow
+	2,  // 2: xgboost.predict.v1.PredictRequest.rows:type_name -> xgboost.predict.v1.Row
+	3,  // 3: xgboost.predict.v1.PredictRequest.options:type_name -> xgboost.predict.v1.PredictOptions
+	5,  // 4: xgboost.predict.v1.PredictResponse.predictions:type_name -> xgboost.predict.v1.Prediction
+	4,  // 5: xgboost.predict.v1.PredictBatchRequest.requests:type_name -> xgboost.predict.v1.PredictRequest
+	6,  // 6: xgboost.predict.v1.PredictBatchResponse.responses:type_name -> xgboost.predict.v1.PredictResponse
+	11, // 7: xgboost.predict.v1.ModelInfo.attributes:type_name -> xgboost.predict.v1.ModelInfo.AttributesEntry
+	4,  // 8: xgboost.predict.v1.Predictor.Predict:input_type -> xgboost.predict.v1.PredictRequest
+	7,  // 9: xgboost.predict.v1.Predictor.PredictBatch:input_type -> xgboost.predict.v1.PredictBatchRequest
+	4,  // 10: xgboost.predict.v1.Predictor.PredictContributions:input_type -> xgboost.predict.v1.PredictRequest
+	9,  // 11: xgboost.predict.v1.Predictor.GetModelInfo:input_type -> xgboost.predict.v1.GetModelInfoRequest
+	4,  // 12: xgboost.predict.v1.Predictor.PredictStream:input_type -> xgboost.predict.v1.PredictRequest
+	6,  // 13: xgboost.predict.v1.Predictor.Predict:output_type -> xgboost.predict.v1.PredictResponse
+	8,  // 14: xgboost.predict.v1.Predictor.PredictBatch:output_type -> xgboost.predict.v1.PredictBatchResponse
+	6,  // 15: xgboost.predict.v1.Predictor.PredictContributions:output_type -> xgboost.predict.v1.PredictResponse
+	10, // 16: xgboost.predict.v1.Predictor.GetModelInfo:output_type -> xgboost.predict.v1.ModelInfo
+	6,  // 17: xgboost.predict.v1.Predictor.PredictStream:output_type -> xgboost.predict.v1.PredictResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_predict_proto_init() }
+func file_predict_proto_init() {
+	if File_predict_proto != nil {
+		return
+	}
+	file_predict_proto_msgTypes[2].OneofWrappers = []any{
+		(*Row_Dense)(nil),
+		(*Row_Sparse)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_predict_proto_rawDesc), len(file_predict_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_predict_proto_goTypes,
+		DependencyIndexes: file_predict_proto_depIdxs,
+		MessageInfos:      file_predict_proto_msgTypes,
+	}.Build()
+	File_predict_proto = out.File
+	file_predict_proto_goTypes = nil
+	file_predict_proto_depIdxs = nil
+}
@@ -0,0 +1,142 @@
+// Package server implements the gRPC Predictor service over the models of a serving.Store.
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/liuhaoXD/xgboost-go/rpc/predictpb"
+	"github.com/liuhaoXD/xgboost-go/serving"
+)
+
+// Server serve the models of Store, routing every request by its model name
+type Server struct {
+	predictpb.UnimplementedPredictorServer
+
+	Store   *serving.Store
+	Batcher *serving.Batcher
+}
+
+// Register register s on a gRPC server
+func (s *Server) Register(g *grpc.Server) {
+	predictpb.RegisterPredictorServer(g, s)
+}
+
+func (s *Server) Predict(ctx context.Context, req *predictpb.PredictRequest) (*predictpb.PredictResponse, error) {
+	return s.predict(req, false)
+}
+
+func (s *Server) PredictContributions(ctx context.Context, req *predictpb.PredictRequest) (*predictpb.PredictResponse, error) {
+	return s.predict(req, true)
+}
+
+func (s *Server) PredictBatch(ctx context.Context, req *predictpb.PredictBatchRequest) (*predictpb.PredictBatchResponse, error) {
+	resp := &predictpb.PredictBatchResponse{Responses: make([]*predictpb.PredictResponse, len(req.GetRequests()))}
+	for i, r := range req.GetRequests() {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		pred, err := s.predict(r, false)
+		if err != nil {
+			st := status.Convert(err)
+			return nil, status.Errorf(st.Code(), "request %d: %s", i, st.Message())
+		}
+		resp.Responses[i] = pred
+	}
+	return resp, nil
+}
+
+func (s *Server) PredictStream(stream predictpb.Predictor_PredictStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		resp, err := s.predict(req, false)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) GetModelInfo(ctx context.Context, req *predictpb.GetModelInfoRequest) (*predictpb.ModelInfo, error) {
+	m, err := s.Store.Get(req.GetModel())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	info, err := m.Info()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &predictpb.ModelInfo{
+		Name:         info.Name,
+		Path:         info.Path,
+		Version:      int32(info.Version),
+		LoadedAtUnix: info.LoadedAt.Unix(),
+		NumTrees:     int32(info.NumTrees),
+		Attributes:   info.Attributes,
+	}, nil
+}
+
+func (s *Server) predict(req *predictpb.PredictRequest, contribs bool) (*predictpb.PredictResponse, error) {
+	m, err := s.Store.Get(req.GetModel())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	rows, err := requestRows(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	opts := serving.PredictOptions{
+		OutputMargin: req.GetOptions().GetOutputMargin(),
+		PredLeaf:     req.GetOptions().GetPredLeaf(),
+		PredContribs: contribs,
+		NTreeLimit:   uint(req.GetOptions().GetNtreeLimit()),
+	}
+	pred, err := s.Batcher.Predict(m, rows, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &predictpb.PredictResponse{
+		Model:        m.Name,
+		ModelVersion: int32(pred.Version),
+		Predictions:  make([]*predictpb.Prediction, len(rows)),
+	}
+	for i := range rows {
+		resp.Predictions[i] = &predictpb.Prediction{Values: pred.Values[i*pred.PerRow : (i+1)*pred.PerRow]}
+	}
+	return resp, nil
+}
+
+func requestRows(req *predictpb.PredictRequest) ([]serving.Row, error) {
+	if len(req.GetRows()) == 0 {
+		return nil, fmt.Errorf("no rows to predict")
+	}
+	rows := make([]serving.Row, len(req.GetRows()))
+	for i, row := range req.GetRows() {
+		switch features := row.GetFeatures().(type) {
+		case *predictpb.Row_Dense:
+			rows[i] = serving.DenseRow(features.Dense.GetValues())
+		case *predictpb.Row_Sparse:
+			if len(features.Sparse.GetIndices()) != len(features.Sparse.GetValues()) {
+				return nil, fmt.Errorf("row %d: %d indices for %d values", i, len(features.Sparse.GetIndices()), len(features.Sparse.GetValues()))
+			}
+			rows[i] = serving.Row{Indices: features.Sparse.GetIndices(), Values: features.Sparse.GetValues()}
+		default:
+			return nil, fmt.Errorf("row %d has no features", i)
+		}
+	}
+	return rows, nil
+}
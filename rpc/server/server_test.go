@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/liuhaoXD/xgboost-go/internal/testbooster"
+	"github.com/liuhaoXD/xgboost-go/rpc/client"
+	"github.com/liuhaoXD/xgboost-go/rpc/predictpb"
+	"github.com/liuhaoXD/xgboost-go/serving"
+)
+
+var testRows = [][]float32{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {2, 4, 8}}
+
+// trainModel train a small regression model, save it at path and return its predictions of testRows
+func trainModel(t *testing.T, path string) []float32 {
+	booster := testbooster.SaveRegression(t, path, 5, nil)
+	defer booster.Free()
+	return testbooster.Predict(t, booster, testRows)
+}
+
+func closeTo(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+// serve start s on an in-process listener and return a client connected to it
+func serve(t *testing.T, s *Server) (*client.Client, func()) {
+	lis := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	s.Register(g)
+	go g.Serve(lis)
+
+	c, err := client.Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, func() {
+		c.Close()
+		g.Stop()
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xgboost-rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model.bin")
+	want := trainModel(t, path)
+
+	store := serving.NewStore()
+	defer store.Close()
+	if err := store.Load("reg", path); err != nil {
+		t.Fatal(err)
+	}
+	c, stop := serve(t, &Server{Store: store, Batcher: &serving.Batcher{MaxRows: 8}})
+	defer stop()
+	ctx := context.Background()
+
+	preds, err := c.PredictDense(ctx, "reg", testRows, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if len(preds[i]) != 1 || !closeTo(preds[i][0], want[i]) {
+			t.Errorf("row %d: served %v, booster %v", i, preds[i], want[i])
+		}
+	}
+
+	nan := float32(math.NaN())
+	sparse, err := c.PredictSparse(ctx, "reg", []client.SparseRow{{Indices: []uint32{1}, Values: []float32{5}}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	missing, err := c.PredictDense(ctx, "reg", [][]float32{{nan, 5, nan}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !closeTo(sparse[0][0], missing[0][0]) {
+		t.Errorf("sparse %v and dense %v predictions differ", sparse, missing)
+	}
+
+	contribs, err := c.Contributions(ctx, "reg", testRows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range contribs {
+		if len(row) != 4 {
+			t.Fatalf("row %d: expected 3 contributions and a bias, got %v", i, row)
+		}
+		var sum float32
+		for _, v := range row {
+			sum += v
+		}
+		margin, err := c.PredictDense(ctx, "reg", testRows[i:i+1], &client.Options{OutputMargin: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(float64(sum-margin[0][0])) > 1e-3 {
+			t.Errorf("row %d: contributions sum to %v, margin %v", i, sum, margin[0][0])
+		}
+	}
+
+	batch, err := c.PredictBatch(ctx, []*predictpb.PredictRequest{
+		client.DenseRequest("reg", testRows[:2], nil),
+		client.DenseRequest("reg", testRows[2:], nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch[0].Predictions) != 2 || !closeTo(batch[1].Predictions[1].Values[0], want[3]) {
+		t.Errorf("unexpected batch responses %v", batch)
+	}
+	if batch[0].ModelVersion != 1 {
+		t.Errorf("predicted with model version %d, expected 1", batch[0].ModelVersion)
+	}
+
+	info, err := c.ModelInfo(ctx, "reg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name != "reg" || info.Version != 1 || info.NumTrees != 5 {
+		t.Errorf("unexpected model info %v", info)
+	}
+
+	stream, err := c.Stream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range testRows {
+		if err := stream.Send(client.DenseRequest("reg", testRows[i:i+1], nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range testRows {
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !closeTo(resp.Predictions[0].Values[0], want[i]) {
+			t.Errorf("stream response %d: %v, want %v", i, resp.Predictions[0].Values, want[i])
+		}
+	}
+	stream.CloseSend()
+
+	if _, err := c.PredictDense(ctx, "missing", testRows, nil); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound for an unknown model, got %v", err)
+	}
+	if _, err := c.PredictDense(ctx, "reg", nil, nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without rows, got %v", err)
+	}
+	bad := []client.SparseRow{{Indices: []uint32{0, 1}, Values: []float32{1}}}
+	if _, err := c.PredictSparse(ctx, "reg", bad, nil); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a malformed sparse row, got %v", err)
+	}
+}
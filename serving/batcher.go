@@ -0,0 +1,91 @@
+package serving
+
+import (
+	"sync"
+	"time"
+)
+
+// Batcher combine concurrent predictions of a model into fewer, larger ones. A batch is
+// run once it holds MaxRows rows or its first request has waited MaxWait.
+type Batcher struct {
+	MaxRows int
+	MaxWait time.Duration
+
+	mu      sync.Mutex
+	pending map[batchKey]*batch
+}
+
+type batchKey struct {
+	model *Model
+	opts  PredictOptions
+}
+
+type batch struct {
+	rows     []Row
+	requests []*batchRequest
+	timer    *time.Timer
+}
+
+type batchRequest struct {
+	offset, count int
+	done          chan struct{}
+	pred          *Prediction
+	err           error
+}
+
+// Predict predict rows with m, batched with other requests with the same options
+func (b *Batcher) Predict(m *Model, rows []Row, opts PredictOptions) (*Prediction, error) {
+	if b == nil || b.MaxRows <= 1 || len(rows) >= b.MaxRows {
+		return m.Predict(rows, opts)
+	}
+
+	key := batchKey{model: m, opts: opts}
+	req := &batchRequest{count: len(rows), done: make(chan struct{})}
+
+	b.mu.Lock()
+	if b.pending == nil {
+		b.pending = map[batchKey]*batch{}
+	}
+	bt := b.pending[key]
+	if bt == nil {
+		bt = &batch{}
+		b.pending[key] = bt
+		bt.timer = time.AfterFunc(b.MaxWait, func() { b.flush(key, bt) })
+	}
+	req.offset = len(bt.rows)
+	bt.rows = append(bt.rows, rows...)
+	bt.requests = append(bt.requests, req)
+	full := len(bt.rows) >= b.MaxRows
+	b.mu.Unlock()
+
+	if full {
+		b.flush(key, bt)
+	}
+	<-req.done
+	return req.pred, req.err
+}
+
+// flush run bt unless another flush already took it
+func (b *Batcher) flush(key batchKey, bt *batch) {
+	b.mu.Lock()
+	if b.pending[key] != bt {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, key)
+	bt.timer.Stop()
+	b.mu.Unlock()
+
+	pred, err := key.model.Predict(bt.rows, key.opts)
+	for _, req := range bt.requests {
+		req.err = err
+		if err == nil {
+			req.pred = &Prediction{
+				Values:  pred.Values[req.offset*pred.PerRow : (req.offset+req.count)*pred.PerRow],
+				PerRow:  pred.PerRow,
+				Version: pred.Version,
+			}
+		}
+		close(req.done)
+	}
+}
@@ -0,0 +1,278 @@
+package serving
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// maxBodySize bounds the size of prediction requests
+const maxBodySize = 64 << 20
+
+// PredictRequest is the body of a JSON prediction request. Rows come either dense,
+// with null for a missing value, or sparse.
+type PredictRequest struct {
+	Dense   [][]*float32   `json:"dense,omitempty"`
+	Sparse  []SparseRow    `json:"sparse,omitempty"`
+	Options PredictOptions `json:"options"`
+}
+
+// SparseRow is a sparse row of a JSON prediction request
+type SparseRow struct {
+	Indices []uint32  `json:"indices"`
+	Values  []float32 `json:"values"`
+}
+
+// PredictResponse is the body of a JSON prediction response, with one list of values per row
+type PredictResponse struct {
+	Model       string      `json:"model"`
+	Version     int         `json:"version"`
+	Predictions [][]float32 `json:"predictions"`
+}
+
+// Handler serve the HTTP API of a Store:
+//
+//	GET  /healthz                            liveness
+//	GET  /readyz                             readiness, once a model is loaded
+//	GET  /v1/models                          info of every model
+//	GET  /v1/models/{name}                   info of a model
+//	POST /v1/models/{name}/predict           JSON prediction
+//	POST /v1/models/{name}/predict/binary    binary prediction
+//
+// A binary request is two little endian uint32, rows and columns, followed by the
+// row-major float32 features with NaN for missing values; options come as query
+// parameters. The response is rows and values per row as uint32, then the float32
+// predictions.
+type Handler struct {
+	Store   *Store
+	Batcher *Batcher
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/healthz":
+		io.WriteString(w, "ok\n")
+	case r.URL.Path == "/readyz":
+		if !h.Store.Ready() {
+			http.Error(w, "no model loaded", http.StatusServiceUnavailable)
+			return
+		}
+		io.WriteString(w, "ok\n")
+	case r.URL.Path == "/v1/models":
+		h.listModels(w, r)
+	case strings.HasPrefix(r.URL.Path, "/v1/models/"):
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/v1/models/"), "/", 2)
+		m, err := h.Store.Get(parts[0])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		var action string
+		if len(parts) > 1 {
+			action = parts[1]
+		}
+		switch action {
+		case "":
+			h.modelInfo(w, r, m)
+		case "predict":
+			h.predictJSON(w, r, m)
+		case "predict/binary":
+			h.predictBinary(w, r, m)
+		default:
+			http.NotFound(w, r)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) listModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	infos := []*ModelInfo{}
+	for _, name := range h.Store.Names() {
+		m, err := h.Store.Get(name)
+		if err != nil {
+			continue
+		}
+		info, err := m.Info()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		infos = append(infos, info)
+	}
+	writeJSON(w, infos)
+}
+
+func (h *Handler) modelInfo(w http.ResponseWriter, r *http.Request, m *Model) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	info, err := m.Info()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, info)
+}
+
+func (h *Handler) predictJSON(w http.ResponseWriter, r *http.Request, m *Model) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req PredictRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+		http.Error(w, "invalid request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	rows, err := req.rows()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pred, err := h.Batcher.Predict(m, rows, req.Options)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resp := PredictResponse{Model: m.Name, Version: pred.Version, Predictions: make([][]float32, len(rows))}
+	for i := range rows {
+		resp.Predictions[i] = pred.Values[i*pred.PerRow : (i+1)*pred.PerRow]
+	}
+	writeJSON(w, resp)
+}
+
+func (req *PredictRequest) rows() ([]Row, error) {
+	if len(req.Dense) > 0 && len(req.Sparse) > 0 {
+		return nil, errors.New("give either dense or sparse rows, not both")
+	}
+	var rows []Row
+	for _, dense := range req.Dense {
+		row := Row{}
+		for j, v := range dense {
+			if v != nil {
+				row.Indices = append(row.Indices, uint32(j))
+				row.Values = append(row.Values, *v)
+			}
+		}
+		rows = append(rows, row)
+	}
+	for i, sparse := range req.Sparse {
+		if len(sparse.Indices) != len(sparse.Values) {
+			return nil, fmt.Errorf("sparse row %d: %d indices for %d values", i, len(sparse.Indices), len(sparse.Values))
+		}
+		rows = append(rows, Row{Indices: sparse.Indices, Values: sparse.Values})
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no rows to predict")
+	}
+	return rows, nil
+}
+
+func (h *Handler) predictBinary(w http.ResponseWriter, r *http.Request, m *Model) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	opts, err := queryOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rows, err := decodeBinaryRows(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pred, err := h.Batcher.Predict(m, rows, opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(encodeBinaryPredictions(pred.Values, len(rows), pred.PerRow))
+}
+
+func queryOptions(r *http.Request) (PredictOptions, error) {
+	q := r.URL.Query()
+	var opts PredictOptions
+	for name, dst := range map[string]*bool{
+		"output_margin": &opts.OutputMargin,
+		"pred_leaf":     &opts.PredLeaf,
+		"pred_contribs": &opts.PredContribs,
+	} {
+		if v := q.Get(name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return opts, fmt.Errorf("invalid %s: %v", name, err)
+			}
+			*dst = b
+		}
+	}
+	if v := q.Get("ntree_limit"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return opts, fmt.Errorf("invalid ntree_limit: %v", err)
+		}
+		opts.NTreeLimit = uint(n)
+	}
+	return opts, nil
+}
+
+func decodeBinaryRows(body []byte) ([]Row, error) {
+	if len(body) < 8 {
+		return nil, errors.New("binary request too short")
+	}
+	rows := int(binary.LittleEndian.Uint32(body[0:]))
+	cols := int(binary.LittleEndian.Uint32(body[4:]))
+	if rows == 0 || cols == 0 {
+		return nil, errors.New("no rows to predict")
+	}
+	if uint64(len(body)-8) != uint64(rows)*uint64(cols)*4 {
+		return nil, fmt.Errorf("binary request holds %d bytes of features, expected %dx%d float32", len(body)-8, rows, cols)
+	}
+	out := make([]Row, rows)
+	features := make([]float32, cols)
+	for i := range out {
+		for j := range features {
+			features[j] = math.Float32frombits(binary.LittleEndian.Uint32(body[8+(i*cols+j)*4:]))
+		}
+		out[i] = DenseRow(features)
+	}
+	return out, nil
+}
+
+func encodeBinaryPredictions(preds []float32, rows int, perRow int) []byte {
+	buf := make([]byte, 8+4*len(preds))
+	binary.LittleEndian.PutUint32(buf[0:], uint32(rows))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(perRow))
+	for i, v := range preds {
+		binary.LittleEndian.PutUint32(buf[8+4*i:], math.Float32bits(v))
+	}
+	return buf
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,157 @@
+package serving
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model.bin")
+	booster := trainModel(t, path, 5)
+	defer booster.Free()
+	want := expected(t, booster)
+
+	store := NewStore()
+	defer store.Close()
+	server := httptest.NewServer(&Handler{Store: store, Batcher: &Batcher{MaxRows: 8}})
+	defer server.Close()
+
+	if resp, err := http.Get(server.URL + "/readyz"); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected not ready without models: %v %v", resp, err)
+	}
+	if err := store.Load("reg", path); err != nil {
+		t.Fatal(err)
+	}
+	for _, endpoint := range []string{"/healthz", "/readyz"} {
+		if resp, err := http.Get(server.URL + endpoint); err != nil || resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: %v %v", endpoint, resp, err)
+		}
+	}
+
+	// dense rows with null for missing values, sparse rows with explicit indices
+	body := `{"dense": [[1, 2, 3], [4, 5, 6], [7, 8, 9], [2, 4, 8]]}`
+	var dense PredictResponse
+	postJSON(t, server.URL+"/v1/models/reg/predict", body, &dense)
+	if dense.Model != "reg" || dense.Version != 1 || len(dense.Predictions) != 4 {
+		t.Fatalf("unexpected response %+v", dense)
+	}
+	for i := range want {
+		if !closeTo(dense.Predictions[i][0], want[i]) {
+			t.Errorf("row %d: served %v, booster %v", i, dense.Predictions[i][0], want[i])
+		}
+	}
+
+	var sparse PredictResponse
+	postJSON(t, server.URL+"/v1/models/reg/predict", `{"sparse": [{"indices": [0, 1, 2], "values": [4, 5, 6]}, {"indices": [1], "values": [5]}]}`, &sparse)
+	var missing PredictResponse
+	postJSON(t, server.URL+"/v1/models/reg/predict", `{"dense": [[4, 5, 6], [null, 5, null]]}`, &missing)
+	if !closeTo(sparse.Predictions[0][0], want[1]) || !closeTo(sparse.Predictions[1][0], missing.Predictions[1][0]) {
+		t.Errorf("sparse %v and dense %v predictions differ", sparse.Predictions, missing.Predictions)
+	}
+
+	var contribs PredictResponse
+	postJSON(t, server.URL+"/v1/models/reg/predict", `{"dense": [[1, 2, 3]], "options": {"pred_contribs": true}}`, &contribs)
+	if len(contribs.Predictions[0]) != 4 {
+		t.Errorf("expected 3 contributions and a bias, got %v", contribs.Predictions[0])
+	}
+
+	// binary: rows, cols, then features
+	buf := new(bytes.Buffer)
+	binary.Write(buf, binary.LittleEndian, []uint32{4, 3})
+	for _, row := range testRows {
+		binary.Write(buf, binary.LittleEndian, row)
+	}
+	resp, err := http.Post(server.URL+"/v1/models/reg/predict/binary", "application/octet-stream", buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("binary predict: %d %s %v", resp.StatusCode, raw, err)
+	}
+	if rows, perRow := binary.LittleEndian.Uint32(raw), binary.LittleEndian.Uint32(raw[4:]); rows != 4 || perRow != 1 {
+		t.Fatalf("binary response shape %dx%d", rows, perRow)
+	}
+	for i := range want {
+		if got := math.Float32frombits(binary.LittleEndian.Uint32(raw[8+4*i:])); !closeTo(got, want[i]) {
+			t.Errorf("binary row %d: served %v, booster %v", i, got, want[i])
+		}
+	}
+
+	var info ModelInfo
+	getJSON(t, server.URL+"/v1/models/reg", &info)
+	if info.Name != "reg" || info.NumTrees != 5 || info.Attributes["owner"] != "serving-test" {
+		t.Errorf("unexpected model info %+v", info)
+	}
+	var infos []ModelInfo
+	getJSON(t, server.URL+"/v1/models", &infos)
+	if len(infos) != 1 {
+		t.Errorf("expected one model, got %+v", infos)
+	}
+
+	if resp, err := http.Get(server.URL + "/v1/models/nope"); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown model: %v %v", resp, err)
+	}
+	if resp, err := http.Post(server.URL+"/v1/models/reg/predict", "application/json", bytes.NewBufferString(`{"dense": []}`)); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for empty request: %v %v", resp, err)
+	}
+
+	// the response carries the version of the booster that predicted
+	time.Sleep(10 * time.Millisecond)
+	reloaded := trainModel(t, path, 10)
+	defer reloaded.Free()
+	m, err := store.Get("reg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	var second PredictResponse
+	postJSON(t, server.URL+"/v1/models/reg/predict", body, &second)
+	if second.Version != 2 || !closeTo(second.Predictions[0][0], expected(t, reloaded)[0]) {
+		t.Errorf("unexpected response after reload %+v", second)
+	}
+}
+
+func postJSON(t *testing.T, url string, body string, out interface{}) {
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("POST %s: %d %s", url, resp.StatusCode, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func getJSON(t *testing.T, url string, out interface{}) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		t.Fatalf("GET %s: %d %s", url, resp.StatusCode, msg)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,227 @@
+// Package serving serves predictions of boosters loaded from model files. Models are
+// kept in a Store, reloaded when their file changes, and concurrent predictions of a
+// model are batched together.
+package serving
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+// Row is a sparse row of features
+type Row struct {
+	Indices []uint32
+	Values  []float32
+}
+
+// DenseRow convert dense features to a Row, treating NaN as missing
+func DenseRow(features []float32) Row {
+	row := Row{}
+	for i, v := range features {
+		if math.IsNaN(float64(v)) {
+			continue
+		}
+		row.Indices = append(row.Indices, uint32(i))
+		row.Values = append(row.Values, v)
+	}
+	return row
+}
+
+// PredictOptions select what Predict computes
+type PredictOptions struct {
+	OutputMargin bool `json:"output_margin,omitempty"`
+	PredLeaf     bool `json:"pred_leaf,omitempty"`
+	PredContribs bool `json:"pred_contribs,omitempty"`
+	NTreeLimit   uint `json:"ntree_limit,omitempty"`
+}
+
+// mask get the option mask of Booster.Predict
+func (o PredictOptions) mask() int {
+	var mask int
+	if o.OutputMargin {
+		mask |= 1
+	}
+	if o.PredLeaf {
+		mask |= 2
+	}
+	if o.PredContribs {
+		mask |= 4
+	}
+	return mask
+}
+
+// Prediction is the output of predicting rows with a model
+type Prediction struct {
+	// Values are the flat predictions, PerRow of them for each row
+	Values []float32
+	PerRow int
+	// Version is the version of the model that made the predictions
+	Version int
+}
+
+// Model is a booster loaded from a model file
+type Model struct {
+	Name string
+	Path string
+
+	mu       sync.Mutex
+	booster  *xgboost.Booster
+	version  int
+	loadedAt time.Time
+	modTime  time.Time
+	size     int64
+}
+
+// ModelInfo describes a loaded model
+type ModelInfo struct {
+	Name       string            `json:"name"`
+	Path       string            `json:"path"`
+	Version    int               `json:"version"`
+	LoadedAt   time.Time         `json:"loaded_at"`
+	NumTrees   int               `json:"num_trees"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// LoadModel load the model file at path
+func LoadModel(name string, path string) (*Model, error) {
+	m := &Model{Name: name, Path: path}
+	if _, err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Reload load the model file again if it changed since it was last loaded. A file that
+// fails to load leaves the current booster in place.
+func (m *Model) Reload() (bool, error) {
+	stat, err := os.Stat(m.Path)
+	if err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	unchanged := m.booster != nil && stat.ModTime().Equal(m.modTime) && stat.Size() == m.size
+	m.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+
+	booster, err := xgboost.BoosterCreate(nil)
+	if err != nil {
+		return false, err
+	}
+	if err := booster.LoadModel(m.Path); err != nil {
+		booster.Free()
+		return false, fmt.Errorf("load %s: %v", m.Path, err)
+	}
+
+	m.mu.Lock()
+	old := m.booster
+	m.booster = booster
+	m.version++
+	m.loadedAt = time.Now()
+	m.modTime = stat.ModTime()
+	m.size = stat.Size()
+	m.mu.Unlock()
+
+	// predictions hold the lock, so nothing uses the old booster any more
+	if old != nil {
+		old.Free()
+	}
+	return true, nil
+}
+
+// Predict predict rows with the current booster of the model
+func (m *Model) Predict(rows []Row, opts PredictOptions) (*Prediction, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("no rows to predict")
+	}
+	matrix, err := rowsMatrix(rows)
+	if err != nil {
+		return nil, err
+	}
+	defer matrix.Free()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.booster == nil {
+		return nil, fmt.Errorf("model %s is closed", m.Name)
+	}
+	preds, err := m.booster.Predict(matrix, opts.mask(), opts.NTreeLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &Prediction{Values: preds, PerRow: len(preds) / len(rows), Version: m.version}, nil
+}
+
+// Info describe the model and its attributes
+func (m *Model) Info() (*ModelInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.booster == nil {
+		return nil, fmt.Errorf("model %s is closed", m.Name)
+	}
+	info := &ModelInfo{
+		Name:       m.Name,
+		Path:       m.Path,
+		Version:    m.version,
+		LoadedAt:   m.loadedAt,
+		Attributes: map[string]string{},
+	}
+	names, err := m.booster.GetAttrNames()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if info.Attributes[name], err = m.booster.GetAttr(name); err != nil {
+			return nil, err
+		}
+	}
+	trees, err := m.booster.DumpModel("", false)
+	if err != nil {
+		return nil, err
+	}
+	info.NumTrees = len(trees)
+	return info, nil
+}
+
+// Close free the booster
+func (m *Model) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.booster == nil {
+		return nil
+	}
+	err := m.booster.Free()
+	m.booster = nil
+	return err
+}
+
+// rowsMatrix build a sparse matrix of rows
+func rowsMatrix(rows []Row) (*xgboost.DMatrix, error) {
+	indptr := make([]uint64, 1, len(rows)+1)
+	var (
+		indices []uint32
+		values  []float32
+		numCol  int
+	)
+	for i, row := range rows {
+		if len(row.Indices) != len(row.Values) {
+			return nil, fmt.Errorf("row %d: %d indices for %d values", i, len(row.Indices), len(row.Values))
+		}
+		for _, idx := range row.Indices {
+			if int(idx) >= numCol {
+				numCol = int(idx) + 1
+			}
+		}
+		indices = append(indices, row.Indices...)
+		values = append(values, row.Values...)
+		indptr = append(indptr, uint64(len(values)))
+	}
+	return xgboost.DMatrixCreateFromCSREx(indptr, indices, values, numCol)
+}
@@ -0,0 +1,143 @@
+package serving
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/internal/testbooster"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+var testRows = model.Matrix{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {2, 4, 8}}
+
+// trainModel train a small regression model for rounds rounds and save it at path
+func trainModel(t *testing.T, path string, rounds int) *xgboost.Booster {
+	return testbooster.SaveRegression(t, path, rounds, map[string]string{"owner": "serving-test"})
+}
+
+// expected predict testRows with booster directly
+func expected(t *testing.T, booster *xgboost.Booster) []float32 {
+	return testbooster.Predict(t, booster, testRows)
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "xgboost-serving")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func closeTo(a, b float32) bool {
+	return math.Abs(float64(a-b)) < 1e-5
+}
+
+func TestModelReload(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model.bin")
+
+	first := trainModel(t, path, 3)
+	defer first.Free()
+	m, err := LoadModel("test", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	rows := make([]Row, len(testRows))
+	for i, r := range testRows {
+		rows[i] = DenseRow(r)
+	}
+	pred, err := m.Predict(rows, PredictOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := expected(t, first)
+	if pred.PerRow != 1 || len(pred.Values) != len(want) {
+		t.Fatalf("got %d predictions with %d per row", len(pred.Values), pred.PerRow)
+	}
+	if pred.Version != 1 {
+		t.Errorf("predicted with version %d, expected 1", pred.Version)
+	}
+	for i := range want {
+		if !closeTo(pred.Values[i], want[i]) {
+			t.Errorf("row %d: served %v, booster %v", i, pred.Values[i], want[i])
+		}
+	}
+
+	if changed, err := m.Reload(); err != nil || changed {
+		t.Errorf("reload of unchanged file: changed %v, err %v", changed, err)
+	}
+
+	// make sure the modification time moves on
+	time.Sleep(10 * time.Millisecond)
+	second := trainModel(t, path, 10)
+	defer second.Free()
+	if changed, err := m.Reload(); err != nil || !changed {
+		t.Fatalf("reload of changed file: changed %v, err %v", changed, err)
+	}
+	info, err := m.Info()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != 2 || info.NumTrees != 10 || info.Attributes["owner"] != "serving-test" {
+		t.Errorf("unexpected info after reload %+v", info)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Reload(); err == nil {
+		t.Error("expected error reloading a broken model file")
+	}
+	pred, err = m.Predict(rows, PredictOptions{})
+	if err != nil {
+		t.Fatalf("broken reload should keep serving the previous model: %v", err)
+	}
+	if pred.Version != 2 {
+		t.Errorf("predicted with version %d after a broken reload, expected 2", pred.Version)
+	}
+}
+
+func TestBatcher(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model.bin")
+	booster := trainModel(t, path, 5)
+	defer booster.Free()
+	want := expected(t, booster)
+
+	m, err := LoadModel("test", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	b := &Batcher{MaxRows: 16, MaxWait: 20 * time.Millisecond}
+	var wg sync.WaitGroup
+	for n := 0; n < 40; n++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pred, err := b.Predict(m, []Row{DenseRow(testRows[i])}, PredictOptions{})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if pred.PerRow != 1 || len(pred.Values) != 1 || !closeTo(pred.Values[0], want[i]) {
+				t.Errorf("row %d: batched %v, booster %v", i, pred.Values, want[i])
+			}
+			if pred.Version != 1 {
+				t.Errorf("row %d: batched with version %d, expected 1", i, pred.Version)
+			}
+		}(n % len(testRows))
+	}
+	wg.Wait()
+}
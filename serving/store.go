@@ -0,0 +1,115 @@
+package serving
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store holds the served models by name
+type Store struct {
+	mu     sync.RWMutex
+	models map[string]*Model
+}
+
+// NewStore create an empty store
+func NewStore() *Store {
+	return &Store{models: map[string]*Model{}}
+}
+
+// Load load the model file at path under name, replacing any model of that name
+func (s *Store) Load(name string, path string) error {
+	m, err := LoadModel(name, path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	old := s.models[name]
+	s.models[name] = m
+	s.mu.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+// Get get the model called name
+func (s *Store) Get(name string) (*Model, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.models[name]
+	if !ok {
+		return nil, fmt.Errorf("model %q not found", name)
+	}
+	return m, nil
+}
+
+// Names list the model names in order
+func (s *Store) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.models))
+	for name := range s.models {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Ready report whether the store has at least one model
+func (s *Store) Ready() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.models) > 0
+}
+
+// Watch reload every model whose file changed, checking every interval until stop is closed
+func (s *Store) Watch(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.ReloadChanged()
+		}
+	}
+}
+
+// ReloadChanged reload every model whose file changed and return their names
+func (s *Store) ReloadChanged() []string {
+	var reloaded []string
+	for _, name := range s.Names() {
+		m, err := s.Get(name)
+		if err != nil {
+			continue
+		}
+		changed, err := m.Reload()
+		if err != nil {
+			log.Printf("reload model %s: %v", name, err)
+			continue
+		}
+		if changed {
+			log.Printf("reloaded model %s from %s", name, m.Path)
+			reloaded = append(reloaded, name)
+		}
+	}
+	return reloaded
+}
+
+// Close free every model
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var first error
+	for name, m := range s.models {
+		if err := m.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(s.models, name)
+	}
+	return first
+}
@@ -0,0 +1,94 @@
+package tracker
+
+import "sort"
+
+// linkMap holds the tree and ring topology handed to the workers
+type linkMap struct {
+	tree   [][]int // tree neighbours of every rank, parent first
+	parent []int   // parent of every rank, -1 for the root
+	ring   [][2]int
+}
+
+// newLinkMap build the topology of n workers the way dmlc-tracker does: a binary
+// tree, and a ring following a depth first walk of the tree, renumbered so that
+// ranks are consecutive along the ring.
+func newLinkMap(n int) *linkMap {
+	tree := make([][]int, n)
+	parent := make([]int, n)
+	for r := 0; r < n; r++ {
+		tree[r] = treeNeighbours(r, n)
+		parent[r] = (r+1)/2 - 1
+	}
+
+	order := shareRing(tree, parent, 0)
+	ring := make([][2]int, n)
+	for i, r := range order {
+		ring[r] = [2]int{order[(i+n-1)%n], order[(i+1)%n]}
+	}
+
+	// renumber so that rank k+1 follows rank k on the ring
+	rmap := make([]int, n)
+	k := 0
+	for i := 1; i < n; i++ {
+		k = ring[k][1]
+		rmap[k] = i
+	}
+
+	m := &linkMap{
+		tree:   make([][]int, n),
+		parent: make([]int, n),
+		ring:   make([][2]int, n),
+	}
+	for r := 0; r < n; r++ {
+		m.ring[rmap[r]] = [2]int{rmap[ring[r][0]], rmap[ring[r][1]]}
+		neighbours := make([]int, len(tree[r]))
+		for i, v := range tree[r] {
+			neighbours[i] = rmap[v]
+		}
+		m.tree[rmap[r]] = neighbours
+		if r == 0 {
+			m.parent[rmap[r]] = -1
+		} else {
+			m.parent[rmap[r]] = rmap[parent[r]]
+		}
+	}
+	return m
+}
+
+func treeNeighbours(rank int, n int) []int {
+	rank++
+	var neighbours []int
+	if rank > 1 {
+		neighbours = append(neighbours, rank/2-1)
+	}
+	if rank*2-1 < n {
+		neighbours = append(neighbours, rank*2-1)
+	}
+	if rank*2 < n {
+		neighbours = append(neighbours, rank*2)
+	}
+	return neighbours
+}
+
+// shareRing list the subtree of r depth first, reversing the last child so that
+// the walk returns next to r
+func shareRing(tree [][]int, parent []int, r int) []int {
+	var children []int
+	for _, v := range tree[r] {
+		if v != parent[r] {
+			children = append(children, v)
+		}
+	}
+	sort.Ints(children)
+	order := []int{r}
+	for i, v := range children {
+		sub := shareRing(tree, parent, v)
+		if i == len(children)-1 {
+			for a, b := 0, len(sub)-1; a < b; a, b = a+1, b-1 {
+				sub[a], sub[b] = sub[b], sub[a]
+			}
+		}
+		order = append(order, sub...)
+	}
+	return order
+}
@@ -0,0 +1,366 @@
+// Package tracker implements the rabit tracker, which assigns ranks to the workers of
+// a distributed job and tells them how to link up into a tree and a ring. It speaks the
+// same wire protocol as dmlc-tracker, so rabit workers need no Python to find each other.
+package tracker
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+)
+
+// magic is exchanged by the tracker and a worker when the worker connects
+const magic = 0xff99
+
+// Tracker coordinates numWorkers rabit workers
+type Tracker struct {
+	// Print receive the messages workers send with RabitTrackerPrint; they go to the
+	// standard logger when nil
+	Print func(msg string)
+
+	listener   net.Listener
+	numWorkers int
+}
+
+// New create a tracker for numWorkers workers listening on addr, e.g. "127.0.0.1:0"
+func New(addr string, numWorkers int) (*Tracker, error) {
+	if numWorkers < 1 {
+		return nil, fmt.Errorf("need at least one worker, got %d", numWorkers)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracker{listener: listener, numWorkers: numWorkers}, nil
+}
+
+// Addr get the address the tracker listens on
+func (t *Tracker) Addr() *net.TCPAddr {
+	return t.listener.Addr().(*net.TCPAddr)
+}
+
+// WorkerEnv get the environment variables pointing rabit workers at the tracker
+func (t *Tracker) WorkerEnv() []string {
+	addr := t.Addr()
+	return []string{
+		"DMLC_TRACKER_URI=" + addr.IP.String(),
+		"DMLC_TRACKER_PORT=" + strconv.Itoa(addr.Port),
+		"DMLC_NUM_WORKER=" + strconv.Itoa(t.numWorkers),
+	}
+}
+
+// Close stop listening, making a running Run return
+func (t *Tracker) Close() error {
+	return t.listener.Close()
+}
+
+// Run serve the workers until all of them have shut down
+func (t *Tracker) Run() error {
+	defer t.listener.Close()
+
+	var (
+		numWorkers = t.numWorkers
+		links      *linkMap
+		todo       []int               // ranks not handed out yet
+		pending    []*worker           // workers waiting for a rank
+		jobs       = map[string]int{}  // task id to rank
+		waitConn   = map[int]*worker{} // workers waiting for peers to connect
+		shutdown   = map[int]bool{}    // ranks done with the job
+	)
+
+	for len(shutdown) != numWorkers {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			return err
+		}
+		w, err := handshake(conn)
+		if err != nil {
+			// not a rabit worker, drop it
+			conn.Close()
+			continue
+		}
+
+		switch w.cmd {
+		case "print":
+			msg, err := w.recvStr()
+			conn.Close()
+			if err != nil {
+				return fmt.Errorf("print from rank %d: %v", w.rank, err)
+			}
+			t.print(msg)
+			continue
+		case "shutdown":
+			conn.Close()
+			if w.rank < 0 || shutdown[w.rank] {
+				return fmt.Errorf("unexpected shutdown of rank %d", w.rank)
+			}
+			if _, ok := waitConn[w.rank]; ok {
+				return fmt.Errorf("rank %d shut down while peers still connect to it", w.rank)
+			}
+			shutdown[w.rank] = true
+			continue
+		case "start", "recover":
+		default:
+			conn.Close()
+			return fmt.Errorf("unknown command %q", w.cmd)
+		}
+
+		if links == nil {
+			if w.cmd != "start" {
+				conn.Close()
+				return fmt.Errorf("%s before any worker started", w.cmd)
+			}
+			if w.worldSize > 0 {
+				numWorkers = w.worldSize
+			}
+			links = newLinkMap(numWorkers)
+			for r := 0; r < numWorkers; r++ {
+				todo = append(todo, r)
+			}
+		} else if w.worldSize != -1 && w.worldSize != numWorkers {
+			conn.Close()
+			return fmt.Errorf("worker reports world size %d, job has %d", w.worldSize, numWorkers)
+		}
+		if w.cmd == "recover" && w.rank < 0 {
+			conn.Close()
+			return errors.New("recover without a rank")
+		}
+
+		rank := w.rank
+		if rank < 0 && w.jobID != "NULL" {
+			if r, ok := jobs[w.jobID]; ok {
+				rank = r
+			}
+		}
+		if rank >= 0 {
+			err := w.assignRank(rank, waitConn, links)
+			conn.Close()
+			if err != nil {
+				return err
+			}
+			if w.waitAccept > 0 {
+				waitConn[rank] = w
+			}
+			continue
+		}
+
+		// hand out the remaining ranks at once, ordered by host, once every worker is in
+		if len(todo) == 0 {
+			conn.Close()
+			return errors.New("more workers than ranks")
+		}
+		pending = append(pending, w)
+		if len(pending) < len(todo) {
+			continue
+		}
+		sort.SliceStable(pending, func(i, j int) bool { return pending[i].host < pending[j].host })
+		for _, p := range pending {
+			rank, todo = todo[0], todo[1:]
+			if p.jobID != "NULL" {
+				jobs[p.jobID] = rank
+			}
+			err := p.assignRank(rank, waitConn, links)
+			p.conn.Close()
+			if err != nil {
+				return err
+			}
+			if p.waitAccept > 0 {
+				waitConn[rank] = p
+			}
+		}
+		pending = nil
+	}
+	return nil
+}
+
+func (t *Tracker) print(msg string) {
+	if t.Print != nil {
+		t.Print(msg)
+		return
+	}
+	log.Print(msg)
+}
+
+// worker is a connection from a rabit worker
+type worker struct {
+	conn       net.Conn
+	host       string
+	port       int
+	rank       int
+	worldSize  int
+	jobID      string
+	cmd        string
+	waitAccept int
+}
+
+func handshake(conn net.Conn) (*worker, error) {
+	w := &worker{conn: conn}
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		w.host = addr.IP.String()
+	}
+	m, err := w.recvInt()
+	if err != nil {
+		return nil, err
+	}
+	if m != magic {
+		return nil, fmt.Errorf("bad magic %#x", m)
+	}
+	if err := w.sendInt(magic); err != nil {
+		return nil, err
+	}
+	if w.rank, err = w.recvInt(); err != nil {
+		return nil, err
+	}
+	if w.worldSize, err = w.recvInt(); err != nil {
+		return nil, err
+	}
+	if w.jobID, err = w.recvStr(); err != nil {
+		return nil, err
+	}
+	if w.cmd, err = w.recvStr(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// assignRank send rank and its links to the worker, then tell it which peers to
+// connect to until it reports every link up
+func (w *worker) assignRank(rank int, waitConn map[int]*worker, links *linkMap) error {
+	w.rank = rank
+	neighbours := map[int]bool{}
+	for _, r := range links.tree[rank] {
+		neighbours[r] = true
+	}
+
+	send := []int{rank, links.parent[rank], len(links.tree), len(links.tree[rank])}
+	send = append(send, links.tree[rank]...)
+	for _, r := range links.ring[rank] {
+		if r == rank {
+			send = append(send, -1)
+			continue
+		}
+		neighbours[r] = true
+		send = append(send, r)
+	}
+	for _, v := range send {
+		if err := w.sendInt(v); err != nil {
+			return fmt.Errorf("rank %d: %v", rank, err)
+		}
+	}
+
+	for {
+		numGood, err := w.recvInt()
+		if err != nil {
+			return fmt.Errorf("rank %d: %v", rank, err)
+		}
+		good := map[int]bool{}
+		for i := 0; i < numGood; i++ {
+			r, err := w.recvInt()
+			if err != nil {
+				return fmt.Errorf("rank %d: %v", rank, err)
+			}
+			if !neighbours[r] {
+				return fmt.Errorf("rank %d reports link to %d, which is not its neighbour", rank, r)
+			}
+			good[r] = true
+		}
+
+		var bad, conn []int
+		for r := range neighbours {
+			if !good[r] {
+				bad = append(bad, r)
+				if _, ok := waitConn[r]; ok {
+					conn = append(conn, r)
+				}
+			}
+		}
+		sort.Ints(conn)
+
+		if err := w.sendInts(len(conn), len(bad)-len(conn)); err != nil {
+			return fmt.Errorf("rank %d: %v", rank, err)
+		}
+		for _, r := range conn {
+			peer := waitConn[r]
+			if err := w.sendStr(peer.host); err != nil {
+				return fmt.Errorf("rank %d: %v", rank, err)
+			}
+			if err := w.sendInts(peer.port, r); err != nil {
+				return fmt.Errorf("rank %d: %v", rank, err)
+			}
+		}
+
+		numErr, err := w.recvInt()
+		if err != nil {
+			return fmt.Errorf("rank %d: %v", rank, err)
+		}
+		if numErr != 0 {
+			continue
+		}
+		if w.port, err = w.recvInt(); err != nil {
+			return fmt.Errorf("rank %d: %v", rank, err)
+		}
+		for _, r := range conn {
+			peer := waitConn[r]
+			peer.waitAccept--
+			if peer.waitAccept == 0 {
+				delete(waitConn, r)
+			}
+		}
+		w.waitAccept = len(bad) - len(conn)
+		return nil
+	}
+}
+
+// ints travel as native (little endian) 32 bit integers, strings as a length and bytes
+
+func (w *worker) recvInt() (int, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(w.conn, buf[:]); err != nil {
+		return 0, err
+	}
+	return int(int32(binary.LittleEndian.Uint32(buf[:]))), nil
+}
+
+func (w *worker) sendInt(v int) error {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(int32(v)))
+	_, err := w.conn.Write(buf[:])
+	return err
+}
+
+func (w *worker) sendInts(values ...int) error {
+	for _, v := range values {
+		if err := w.sendInt(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *worker) recvStr() (string, error) {
+	n, err := w.recvInt()
+	if err != nil {
+		return "", err
+	}
+	if n < 0 {
+		return "", fmt.Errorf("negative string length %d", n)
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(w.conn, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func (w *worker) sendStr(s string) error {
+	if err := w.sendInt(len(s)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.conn, s)
+	return err
+}
@@ -0,0 +1,142 @@
+package tracker
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkMap(t *testing.T) {
+	// expected values computed with dmlc-tracker's get_link_map
+	cases := []struct {
+		n      int
+		tree   [][]int
+		parent []int
+		ring   [][2]int
+	}{
+		{1, [][]int{{}}, []int{-1}, [][2]int{{0, 0}}},
+		{2, [][]int{{1}, {0}}, []int{-1, 0}, [][2]int{{1, 1}, {0, 0}}},
+		{4, [][]int{{1, 3}, {0, 2}, {1}, {0}}, []int{-1, 0, 1, 0}, [][2]int{{3, 1}, {0, 2}, {1, 3}, {2, 0}}},
+		{7, [][]int{{1, 6}, {0, 2, 3}, {1}, {1}, {6}, {6}, {0, 5, 4}}, []int{-1, 0, 1, 1, 6, 6, 0},
+			[][2]int{{6, 1}, {0, 2}, {1, 3}, {2, 4}, {3, 5}, {4, 6}, {5, 0}}},
+	}
+	for _, c := range cases {
+		m := newLinkMap(c.n)
+		if !reflect.DeepEqual(m.tree, c.tree) {
+			t.Errorf("n=%d: tree %v, expected %v", c.n, m.tree, c.tree)
+		}
+		if !reflect.DeepEqual(m.parent, c.parent) {
+			t.Errorf("n=%d: parent %v, expected %v", c.n, m.parent, c.parent)
+		}
+		if !reflect.DeepEqual(m.ring, c.ring) {
+			t.Errorf("n=%d: ring %v, expected %v", c.n, m.ring, c.ring)
+		}
+	}
+}
+
+// fakeWorker speaks the worker side of the protocol without linking to peers
+type fakeWorker struct {
+	*worker
+}
+
+func dial(t *testing.T, tr *Tracker, rank int, jobID string, cmd string) *fakeWorker {
+	conn, err := net.Dial("tcp", tr.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &fakeWorker{&worker{conn: conn}}
+	if err := w.sendInt(magic); err != nil {
+		t.Fatal(err)
+	}
+	if m, err := w.recvInt(); err != nil || m != magic {
+		t.Fatalf("bad magic reply %#x: %v", m, err)
+	}
+	if err := w.sendInts(rank, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.sendStr(jobID); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.sendStr(cmd); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func (w *fakeWorker) recvInts(t *testing.T, n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		v, err := w.recvInt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values[i] = v
+	}
+	return values
+}
+
+func TestTrackerProtocol(t *testing.T) {
+	tr, err := New("127.0.0.1:0", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var printed []string
+	tr.Print = func(msg string) { printed = append(printed, msg) }
+	done := make(chan error, 1)
+	go func() { done <- tr.Run() }()
+
+	// ranks are handed out once both workers are in
+	a := dial(t, tr, -1, "0", "start")
+	b := dial(t, tr, -1, "1", "start")
+
+	// rank, parent, world size, tree neighbours, prev, next
+	if got := a.recvInts(t, 7); !reflect.DeepEqual(got, []int{0, -1, 2, 1, 1, 1, 1}) {
+		t.Fatalf("rank 0 got assignment %v", got)
+	}
+	// no links up yet, nobody to connect to, so wait for one peer
+	if err := a.sendInt(0); err != nil {
+		t.Fatal(err)
+	}
+	if got := a.recvInts(t, 2); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Fatalf("rank 0 got connect plan %v", got)
+	}
+	if err := a.sendInts(0, 9000); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := b.recvInts(t, 7); !reflect.DeepEqual(got, []int{1, 0, 2, 1, 0, 0, 0}) {
+		t.Fatalf("rank 1 got assignment %v", got)
+	}
+	if err := b.sendInt(0); err != nil {
+		t.Fatal(err)
+	}
+	// connect to rank 0, which is waiting
+	if got := b.recvInts(t, 2); !reflect.DeepEqual(got, []int{1, 0}) {
+		t.Fatalf("rank 1 got connect plan %v", got)
+	}
+	host, err := b.recvStr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.recvInts(t, 2); host != "127.0.0.1" || !reflect.DeepEqual(got, []int{9000, 0}) {
+		t.Fatalf("rank 1 told to connect to %s %v", host, got)
+	}
+	if err := b.sendInts(0, 9001); err != nil {
+		t.Fatal(err)
+	}
+
+	p := dial(t, tr, 1, "1", "print")
+	if err := p.sendStr("progress 50%"); err != nil {
+		t.Fatal(err)
+	}
+
+	dial(t, tr, 0, "0", "shutdown")
+	dial(t, tr, 1, "1", "shutdown")
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(printed, "") != "progress 50%" {
+		t.Errorf("unexpected printed messages %q", printed)
+	}
+}
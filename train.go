@@ -0,0 +1,78 @@
+package xgboost
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// boostedRoundsAttr is the booster attribute used to remember how many rounds have been boosted
+const boostedRoundsAttr = "boosted_rounds"
+
+// Params holds booster parameters, keyed by the names accepted by SetParam
+type Params map[string]string
+
+// SetParams set every parameter of params, in key order
+func (booster *Booster) SetParams(params Params) error {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := booster.SetParam(k, params[k]); err != nil {
+			return fmt.Errorf("set param %s=%s: %v", k, params[k], err)
+		}
+	}
+	return nil
+}
+
+// BoostedRounds get the number of boosting rounds already in the model. Models
+// trained through this package record it as an attribute; for other models it is
+// derived from the tree count, using num_class and num_parallel_tree of params.
+func (booster *Booster) BoostedRounds(params Params) (int, error) {
+	attr, err := booster.GetAttr(boostedRoundsAttr)
+	if err != nil {
+		return 0, err
+	}
+	if attr != "" {
+		rounds, err := strconv.Atoi(attr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s attribute %q: %v", boostedRoundsAttr, attr, err)
+		}
+		return rounds, nil
+	}
+
+	trees, err := booster.DumpModel("", false)
+	if err != nil {
+		return 0, err
+	}
+	perRound := 1
+	for _, name := range []string{"num_class", "num_parallel_tree"} {
+		if v, ok := params[name]; ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s %q: %v", name, v, err)
+			}
+			if n > 1 {
+				perRound *= n
+			}
+		}
+	}
+	return len(trees) / perRound, nil
+}
+
+// Train create a booster with params and boost it rounds times on dtrain
+func Train(params Params, dtrain *DMatrix, rounds int) (*Booster, error) {
+	return TrainContext(context.Background(), params, dtrain, rounds, TrainOptions{})
+}
+
+// ContinueTraining load model and boost it extraRounds more times on dtrain.
+// Iterations are numbered after the rounds already in the model. With
+// process_type=update (e.g. updater=refresh or prune) the existing trees are
+// updated in place instead, starting again from the first round; extraRounds
+// may then not exceed the rounds already in the model.
+func ContinueTraining(model []byte, dtrain *DMatrix, extraRounds int, params Params) (*Booster, error) {
+	return ContinueTrainingContext(context.Background(), model, dtrain, extraRounds, params, TrainOptions{})
+}
@@ -0,0 +1,132 @@
+package xgboost
+
+import (
+	"math"
+	"testing"
+)
+
+func trainTestMatrix(t *testing.T) *DMatrix {
+	rows, cols := 20, 3
+	data := make([][]float32, rows)
+	labels := make([]float32, rows)
+	for i := 0; i < rows; i++ {
+		row := make([]float32, cols)
+		for j := 0; j < cols; j++ {
+			row[j] = float32((i + 1) * (j + 1))
+		}
+		data[i] = row
+		labels[i] = float32(1 + i*i)
+	}
+	matrix, err := DMatrixCreateFromMat(data, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := matrix.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	return matrix
+}
+
+func TestContinueTraining(t *testing.T) {
+	dtrain := trainTestMatrix(t)
+	defer dtrain.Free()
+
+	params := Params{
+		"objective": "reg:linear",
+		"max_depth": "3",
+		"eta":       "0.3",
+		"silent":    "1",
+	}
+
+	full, err := Train(params, dtrain, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer full.Free()
+
+	half, err := Train(params, dtrain, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer half.Free()
+	raw, err := half.GetModelRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := ContinueTraining(raw, dtrain, 10, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resumed.Free()
+
+	rounds, err := resumed.BoostedRounds(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rounds != 20 {
+		t.Errorf("expected 20 boosted rounds, got %d", rounds)
+	}
+	trees, err := resumed.DumpModel("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) != 20 {
+		t.Errorf("expected 20 trees, got %d", len(trees))
+	}
+
+	want, err := full.Predict(dtrain, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := resumed.Predict(dtrain, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if math.Abs(float64(want[i]-got[i])) > 1e-4 {
+			t.Errorf("prediction %d: resumed %v, uninterrupted %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestContinueTrainingRefresh(t *testing.T) {
+	dtrain := trainTestMatrix(t)
+	defer dtrain.Free()
+
+	params := Params{"objective": "reg:linear", "max_depth": "3", "silent": "1"}
+	booster, err := Train(params, dtrain, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer booster.Free()
+	raw, err := booster.GetModelRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refresh := Params{
+		"objective":    "reg:linear",
+		"process_type": "update",
+		"updater":      "refresh",
+		"refresh_leaf": "1",
+		"silent":       "1",
+	}
+	if _, err := ContinueTraining(raw, dtrain, 6, refresh); err == nil {
+		t.Error("expected error when updating more rounds than the model has")
+	}
+
+	refreshed, err := ContinueTraining(raw, dtrain, 5, refresh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer refreshed.Free()
+
+	trees, err := refreshed.DumpModel("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) != 5 {
+		t.Errorf("expected refresh to keep 5 trees, got %d", len(trees))
+	}
+}
@@ -0,0 +1,43 @@
+package xgboost
+
+import (
+	"fmt"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// TreeModel parse the trees of a tree booster from their json dump with stats, for
+// prediction and explanation in pure Go
+func (booster *Booster) TreeModel() (*model.TreeModel, error) {
+	params, err := booster.LearnerParams()
+	if err != nil {
+		return nil, err
+	}
+	if params.Booster != "gbtree" {
+		// dart weights its trees, which the dump leaves out
+		return nil, fmt.Errorf("cannot parse the trees of a %s booster", params.Booster)
+	}
+	dumps, err := booster.DumpModelEx("", true, "json")
+	if err != nil {
+		return nil, err
+	}
+	trees, err := model.ParseTrees(dumps)
+	if err != nil {
+		return nil, err
+	}
+	m := &model.TreeModel{
+		Trees:      trees,
+		TreeGroups: params.TreeGroups,
+		NumGroup:   1,
+		NumFeature: params.NumFeature,
+		BaseMargin: params.BaseScore,
+		Objective:  params.Objective,
+	}
+	if params.NumClass > 1 {
+		m.NumGroup = params.NumClass
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
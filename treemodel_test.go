@@ -0,0 +1,106 @@
+package xgboost
+
+import (
+	"math"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// shapTestData is a dataset with a missing value in every fifth row
+func shapTestData(t *testing.T, numClass int) (*DMatrix, model.Matrix) {
+	rows := make(model.Matrix, 60)
+	labels := make([]float32, len(rows))
+	for i := range rows {
+		rows[i] = []float32{float32(i % 10), float32(i%7) * 0.5, float32((i * 3) % 11)}
+		if i%5 == 0 {
+			rows[i][i%3] = float32(math.NaN())
+		}
+		labels[i] = float32(i % numClass)
+		if numClass == 1 {
+			labels[i] = float32(i%10) + float32(i%7)*0.3
+		}
+	}
+	dm, err := DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+	return dm, rows
+}
+
+func closeEnough(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-4*math.Max(1, math.Abs(b))
+}
+
+func TestTreeModelSHAP(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		numClass int
+		params   Params
+	}{
+		{"regression", 1, Params{"objective": "reg:linear", "base_score": "2"}},
+		{"binary", 2, Params{"objective": "binary:logistic"}},
+		{"multiclass", 3, Params{"objective": "multi:softprob", "num_class": "3"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			dm, rows := shapTestData(t, c.numClass)
+			defer dm.Free()
+			c.params["max_depth"] = "4"
+			c.params["silent"] = "1"
+			booster, err := Train(c.params, dm, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer booster.Free()
+			m, err := booster.TreeModel()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			preds, err := booster.Predict(dm, 0, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			contribs, err := booster.Predict(dm, 4, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			interactions, err := booster.Predict(dm, 16, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			columns := m.NumFeature + 1
+			for i, row := range rows {
+				pred, err := m.Predict(row)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for g, v := range pred {
+					if want := preds[i*len(pred)+g]; !closeEnough(float64(v), float64(want)) {
+						t.Errorf("row %d group %d: predicted %v, booster %v", i, g, v, want)
+					}
+				}
+				for g, phi := range m.SHAP(row) {
+					for j, v := range phi {
+						if want := contribs[(i*m.NumGroup+g)*columns+j]; !closeEnough(v, float64(want)) {
+							t.Errorf("row %d group %d feature %d: SHAP %v, booster %v", i, g, j, v, want)
+						}
+					}
+				}
+				for g, matrix := range m.SHAPInteractions(row) {
+					for j, values := range matrix {
+						for k, v := range values {
+							want := interactions[((i*m.NumGroup+g)*columns+j)*columns+k]
+							if !closeEnough(v, float64(want)) {
+								t.Errorf("row %d group %d: interaction %d,%d is %v, booster %v", i, g, j, k, v, want)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+// Package tune searches booster parameters with grid search, random search,
+// successive halving and Hyperband, scoring every trial by cross validation.
+package tune
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+// Scorer score params after training rounds boosting rounds
+type Scorer func(params xgboost.Params, rounds int) (Score, error)
+
+// Score is the score of a trial. Metric names what Value measures and Maximize
+// whether a larger Value is better; without a Metric the search takes the direction
+// from its Options.
+type Score struct {
+	Value    float64
+	Metric   string
+	Maximize bool
+}
+
+// ScoreFunc adapt a function giving bare scores to a Scorer, the direction of its
+// scores coming from the search Options
+func ScoreFunc(score func(params xgboost.Params, rounds int) (float64, error)) Scorer {
+	return func(params xgboost.Params, rounds int) (Score, error) {
+		value, err := score(params, rounds)
+		return Score{Value: value}, err
+	}
+}
+
+// CVScorer score params by the mean test metric of CV over data at its best round,
+// reporting the metric CV watched and its direction. base holds the parameters
+// shared by every trial.
+func CVScorer(base xgboost.Params, data *xgboost.DMatrix, nfold int, opts xgboost.CVOptions) Scorer {
+	return func(params xgboost.Params, rounds int) (Score, error) {
+		merged := xgboost.Params{}
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range params {
+			merged[k] = v
+		}
+		result, err := xgboost.CV(merged, data, nfold, rounds, opts)
+		if err != nil {
+			return Score{}, err
+		}
+		return Score{Value: result.BestScore, Metric: result.Watched, Maximize: result.Maximize}, nil
+	}
+}
+
+// Options control a search
+type Options struct {
+	// Rounds is the number of boosting rounds of every trial; for SuccessiveHalving
+	// and Hyperband it is the largest budget given to a trial
+	Rounds int
+	// Concurrency bounds the number of trials run at once (1 when 0)
+	Concurrency int
+	// Metric is the metric of scores that do not name theirs, as from ScoreFunc
+	Metric string
+	// Maximize tells whether a larger score is better for scores that do not name
+	// their metric; guessed from Metric when nil
+	Maximize *bool
+	// Seed seeds the sampling of parameters
+	Seed int64
+}
+
+// maximize tell whether a larger score of trials is better, as reported by the scorer
+// or else from opts. Trials reporting different metrics are an error.
+func (opts Options) maximize(trials []Trial) (bool, error) {
+	var scored *Trial
+	for i := range trials {
+		if trials[i].Err != nil || trials[i].Metric == "" {
+			continue
+		}
+		if scored == nil {
+			scored = &trials[i]
+		} else if trials[i].Metric != scored.Metric || trials[i].Maximize != scored.Maximize {
+			return false, fmt.Errorf("trials scored by %s and %s", scored.Metric, trials[i].Metric)
+		}
+	}
+	if scored != nil {
+		return scored.Maximize, nil
+	}
+	if opts.Maximize != nil {
+		return *opts.Maximize, nil
+	}
+	return xgboost.MetricMaximize(strings.TrimPrefix(opts.Metric, "test-")), nil
+}
+
+// Trial is one scored parameter combination
+type Trial struct {
+	Params xgboost.Params
+	Rounds int
+	Score  float64
+	// Metric and Maximize are as reported by the scorer
+	Metric   string
+	Maximize bool
+	Err      error
+}
+
+// Result holds every trial of a search and the best one
+type Result struct {
+	Trials []Trial
+	Best   Trial
+}
+
+// GridSearch score every combination of the grids of space
+func GridSearch(space Space, score Scorer, opts Options) (*Result, error) {
+	return search(space.grid(), score, opts)
+}
+
+// RandomSearch score n random combinations drawn from space
+func RandomSearch(space Space, n int, score Scorer, opts Options) (*Result, error) {
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	return search(space.sample(rnd, n), score, opts)
+}
+
+func search(combos []xgboost.Params, score Scorer, opts Options) (*Result, error) {
+	if opts.Rounds <= 0 {
+		return nil, errors.New("rounds must be positive")
+	}
+	trials := make([]Trial, len(combos))
+	for i, params := range combos {
+		trials[i] = Trial{Params: params, Rounds: opts.Rounds}
+	}
+	runTrials(trials, score, opts.Concurrency)
+	return newResult(trials, opts)
+}
+
+// SuccessiveHalving draw n random combinations from space and score them with minRounds
+// rounds. The best 1/eta of them are scored again with eta times more rounds, until one
+// combination is left or opts.Rounds is reached.
+func SuccessiveHalving(space Space, n int, minRounds int, eta int, score Scorer, opts Options) (*Result, error) {
+	if eta < 2 {
+		return nil, fmt.Errorf("eta must be at least 2, got %d", eta)
+	}
+	if minRounds <= 0 || minRounds > opts.Rounds {
+		return nil, fmt.Errorf("min rounds %d must be in [1, %d]", minRounds, opts.Rounds)
+	}
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	trials := halving(space.sample(rnd, n), minRounds, eta, score, opts)
+	return newResult(trials, opts)
+}
+
+// Hyperband run successive halving brackets trading the number of combinations against
+// the rounds given to each, with opts.Rounds as the largest budget.
+func Hyperband(space Space, eta int, score Scorer, opts Options) (*Result, error) {
+	if eta < 2 {
+		return nil, fmt.Errorf("eta must be at least 2, got %d", eta)
+	}
+	if opts.Rounds <= 0 {
+		return nil, errors.New("rounds must be positive")
+	}
+	rnd := rand.New(rand.NewSource(opts.Seed))
+	sMax := int(math.Floor(math.Log(float64(opts.Rounds))/math.Log(float64(eta)) + 1e-9))
+
+	var trials []Trial
+	for s := sMax; s >= 0; s-- {
+		n := int(math.Ceil(float64(sMax+1) / float64(s+1) * math.Pow(float64(eta), float64(s))))
+		rounds := int(float64(opts.Rounds) * math.Pow(float64(eta), float64(-s)))
+		if rounds < 1 {
+			rounds = 1
+		}
+		trials = append(trials, halving(space.sample(rnd, n), rounds, eta, score, opts)...)
+	}
+	return newResult(trials, opts)
+}
+
+func halving(combos []xgboost.Params, rounds int, eta int, score Scorer, opts Options) []Trial {
+	var all []Trial
+	for {
+		trials := make([]Trial, len(combos))
+		for i, params := range combos {
+			trials[i] = Trial{Params: params, Rounds: rounds}
+		}
+		runTrials(trials, score, opts.Concurrency)
+		all = append(all, trials...)
+
+		keep := len(combos) / eta
+		if keep < 1 || rounds >= opts.Rounds {
+			return all
+		}
+		ok := successful(trials)
+		maximize, err := opts.maximize(ok)
+		if err != nil {
+			// newResult reports the trials of different metrics
+			return all
+		}
+		sortTrials(ok, maximize)
+		if keep > len(ok) {
+			keep = len(ok)
+		}
+		if keep == 0 {
+			return all
+		}
+		combos = combos[:0]
+		for _, trial := range ok[:keep] {
+			combos = append(combos, trial.Params)
+		}
+		rounds *= eta
+		if rounds > opts.Rounds {
+			rounds = opts.Rounds
+		}
+	}
+}
+
+// runTrials score trials in place, at most concurrency at a time
+func runTrials(trials []Trial, score Scorer, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range trials {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(trial *Trial) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			var result Score
+			result, trial.Err = score(trial.Params, trial.Rounds)
+			trial.Score, trial.Metric, trial.Maximize = result.Value, result.Metric, result.Maximize
+		}(&trials[i])
+	}
+	wg.Wait()
+}
+
+// newResult pick the best trial among those given the largest budget
+func newResult(trials []Trial, opts Options) (*Result, error) {
+	ok := successful(trials)
+	if len(ok) == 0 {
+		if len(trials) > 0 {
+			return nil, fmt.Errorf("every trial failed, first error: %v", trials[0].Err)
+		}
+		return nil, errors.New("no trials to run")
+	}
+	maximize, err := opts.maximize(ok)
+	if err != nil {
+		return nil, err
+	}
+	var maxRounds int
+	for _, trial := range ok {
+		if trial.Rounds > maxRounds {
+			maxRounds = trial.Rounds
+		}
+	}
+	var finalists []Trial
+	for _, trial := range ok {
+		if trial.Rounds == maxRounds {
+			finalists = append(finalists, trial)
+		}
+	}
+	sortTrials(finalists, maximize)
+	return &Result{Trials: trials, Best: finalists[0]}, nil
+}
+
+func successful(trials []Trial) []Trial {
+	var ok []Trial
+	for _, trial := range trials {
+		if trial.Err == nil && !math.IsNaN(trial.Score) {
+			ok = append(ok, trial)
+		}
+	}
+	return ok
+}
+
+func sortTrials(trials []Trial, maximize bool) {
+	sort.SliceStable(trials, func(i, j int) bool {
+		if maximize {
+			return trials[i].Score > trials[j].Score
+		}
+		return trials[i].Score < trials[j].Score
+	})
+}
+
+// WriteTable write every trial as an aligned table, followed by the best parameters
+func (r *Result) WriteTable(w io.Writer) error {
+	keySet := map[string]bool{}
+	for _, trial := range r.Trials {
+		for k := range trial.Params {
+			keySet[k] = true
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for k := range keySet {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "trial\trounds\tscore\t%s\n", strings.Join(keys, "\t"))
+	for i, trial := range r.Trials {
+		score := formatFloat(trial.Score)
+		if trial.Err != nil {
+			score = "error: " + trial.Err.Error()
+		}
+		values := make([]string, len(keys))
+		for j, k := range keys {
+			values[j] = trial.Params[k]
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%s\t%s\n", i, trial.Rounds, score, strings.Join(values, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	best := make([]string, len(keys))
+	for i, k := range keys {
+		best[i] = k + "=" + r.Best.Params[k]
+	}
+	_, err := fmt.Fprintf(w, "best: score %s after %d rounds with %s\n", formatFloat(r.Best.Score), r.Best.Rounds, strings.Join(best, " "))
+	return err
+}
@@ -0,0 +1,210 @@
+package tune
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/liuhaoXD/xgboost-go"
+	"github.com/liuhaoXD/xgboost-go/model"
+)
+
+// quadratic is a fake scorer with its minimum at max_depth=4, eta=0.1, improving with rounds
+func quadratic(params xgboost.Params, rounds int) (float64, error) {
+	depth, err := strconv.Atoi(params["max_depth"])
+	if err != nil {
+		return 0, err
+	}
+	eta, err := strconv.ParseFloat(params["eta"], 64)
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(float64(depth-4), 2) + math.Pow(eta-0.1, 2)*100 + 1/float64(rounds), nil
+}
+
+var testSpace = Space{
+	IntRange{Name: "max_depth", Min: 2, Max: 8, Step: 2},
+	FloatRange{Name: "eta", Min: 0.01, Max: 1, Steps: 3, Log: true},
+}
+
+func TestGridSearch(t *testing.T) {
+	result, err := GridSearch(testSpace, ScoreFunc(quadratic), Options{Rounds: 10, Concurrency: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trials) != 12 {
+		t.Errorf("expected 12 trials, got %d", len(result.Trials))
+	}
+	if result.Best.Params["max_depth"] != "4" || result.Best.Params["eta"] != "0.1" {
+		t.Errorf("unexpected best params %v", result.Best.Params)
+	}
+
+	var buf bytes.Buffer
+	if err := result.WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 14 {
+		t.Errorf("expected header, 12 trials and best line, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "best: score") {
+		t.Errorf("missing best line:\n%s", buf.String())
+	}
+}
+
+func TestRandomSearchConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	running, peak := 0, 0
+	score := func(params xgboost.Params, rounds int) (float64, error) {
+		mu.Lock()
+		running++
+		if running > peak {
+			peak = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		return quadratic(params, rounds)
+	}
+
+	result, err := RandomSearch(testSpace, 20, ScoreFunc(score), Options{Rounds: 5, Concurrency: 2, Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Trials) != 20 {
+		t.Errorf("expected 20 trials, got %d", len(result.Trials))
+	}
+	if peak > 2 {
+		t.Errorf("ran %d trials at once, limit is 2", peak)
+	}
+}
+
+func TestSuccessiveHalving(t *testing.T) {
+	result, err := SuccessiveHalving(testSpace, 9, 1, 3, ScoreFunc(quadratic), Options{Rounds: 9, Concurrency: 4, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 9 trials with 1 round, 3 with 3 rounds, 1 with 9 rounds
+	if len(result.Trials) != 13 {
+		t.Errorf("expected 13 trials, got %d", len(result.Trials))
+	}
+	if result.Best.Rounds != 9 {
+		t.Errorf("expected best trial with 9 rounds, got %d", result.Best.Rounds)
+	}
+}
+
+func TestHyperband(t *testing.T) {
+	result, err := Hyperband(testSpace, 3, ScoreFunc(quadratic), Options{Rounds: 9, Concurrency: 4, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Best.Rounds != 9 {
+		t.Errorf("expected best trial with 9 rounds, got %d", result.Best.Rounds)
+	}
+	best, _ := quadratic(result.Best.Params, 9)
+	for _, trial := range result.Trials {
+		if trial.Rounds == 9 && trial.Score < best {
+			t.Errorf("trial %v beats best %v", trial, result.Best)
+		}
+	}
+}
+
+func TestCVScorer(t *testing.T) {
+	rows := make(model.Matrix, 90)
+	labels := make([]float32, len(rows))
+	for i := range rows {
+		rows[i] = []float32{float32(i % 10), float32((i * 7) % 13), float32(i % 3)}
+		if i%10+i%3 > 6 {
+			labels[i] = 1
+		}
+	}
+	data, err := xgboost.DMatrixCreateFromMat(rows, float32(math.NaN()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer data.Free()
+	if err := data.SetLabels(labels); err != nil {
+		t.Fatal(err)
+	}
+
+	// neither the CV nor the search options name the metric, the scorer reports auc
+	base := xgboost.Params{"objective": "binary:logistic", "eval_metric": "auc", "silent": "1"}
+	score := CVScorer(base, data, 3, xgboost.CVOptions{Seed: 1})
+	space := Space{IntRange{Name: "max_depth", Min: 1, Max: 3, Step: 2}}
+	result, err := GridSearch(space, score, Options{Rounds: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, trial := range result.Trials {
+		if trial.Err != nil {
+			t.Fatalf("trial %v: %v", trial.Params, trial.Err)
+		}
+		if trial.Metric != "test-auc" || !trial.Maximize {
+			t.Errorf("trial %v scored by %s, maximize %v", trial.Params, trial.Metric, trial.Maximize)
+		}
+		if trial.Score <= 0.5 || trial.Score > 1 {
+			t.Errorf("trial %v has auc %v", trial.Params, trial.Score)
+		}
+		if trial.Score > result.Best.Score {
+			t.Errorf("best trial has auc %v, %v has %v", result.Best.Score, trial.Params, trial.Score)
+		}
+	}
+}
+
+func TestSearchDirection(t *testing.T) {
+	// a scorer naming its metric decides the direction over the options
+	negated := func(params xgboost.Params, rounds int) (Score, error) {
+		value, err := quadratic(params, rounds)
+		return Score{Value: -value, Metric: "test-auc", Maximize: true}, err
+	}
+	minimize := false
+	result, err := GridSearch(testSpace, negated, Options{Rounds: 10, Maximize: &minimize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Best.Params["max_depth"] != "4" {
+		t.Errorf("best trial %v, expected max_depth 4", result.Best.Params)
+	}
+
+	for _, c := range []struct {
+		opts Options
+		want bool
+	}{
+		{Options{}, false},
+		{Options{Metric: "rmse"}, false},
+		{Options{Metric: "test-auc"}, true},
+		{Options{Metric: "auc", Maximize: &minimize}, false},
+	} {
+		if got, err := c.opts.maximize(nil); err != nil || got != c.want {
+			t.Errorf("%+v: maximize %v, %v; expected %v", c.opts, got, err, c.want)
+		}
+	}
+
+	mixed := func(params xgboost.Params, rounds int) (Score, error) {
+		if params["max_depth"] == "2" {
+			return Score{Value: 1, Metric: "test-rmse"}, nil
+		}
+		return Score{Value: 0.5, Metric: "test-auc", Maximize: true}, nil
+	}
+	if _, err := GridSearch(testSpace, mixed, Options{Rounds: 1}); err == nil {
+		t.Error("expected an error for trials of different metrics")
+	}
+}
+
+func TestSearchErrors(t *testing.T) {
+	failing := func(params xgboost.Params, rounds int) (float64, error) {
+		return 0, errors.New("boom")
+	}
+	if _, err := GridSearch(testSpace, ScoreFunc(failing), Options{Rounds: 1}); err == nil {
+		t.Error("expected error when every trial fails")
+	}
+	if _, err := SuccessiveHalving(testSpace, 9, 1, 1, ScoreFunc(quadratic), Options{Rounds: 9}); err == nil {
+		t.Error("expected error for eta < 2")
+	}
+}
@@ -0,0 +1,136 @@
+package tune
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/liuhaoXD/xgboost-go"
+)
+
+// Param is one dimension of a search space
+type Param interface {
+	// Key get the booster parameter name
+	Key() string
+	// Grid get the values tried by a grid search
+	Grid() []string
+	// Sample draw a random value
+	Sample(rnd *rand.Rand) string
+}
+
+// Choice is a parameter taking one of a fixed list of values
+type Choice struct {
+	Name   string
+	Values []string
+}
+
+func (p Choice) Key() string    { return p.Name }
+func (p Choice) Grid() []string { return p.Values }
+
+func (p Choice) Sample(rnd *rand.Rand) string {
+	return p.Values[rnd.Intn(len(p.Values))]
+}
+
+// IntRange is an integer parameter in [Min, Max], in increments of Step (1 when 0)
+type IntRange struct {
+	Name     string
+	Min, Max int
+	Step     int
+}
+
+func (p IntRange) Key() string { return p.Name }
+
+func (p IntRange) step() int {
+	if p.Step <= 0 {
+		return 1
+	}
+	return p.Step
+}
+
+func (p IntRange) Grid() []string {
+	var values []string
+	for v := p.Min; v <= p.Max; v += p.step() {
+		values = append(values, strconv.Itoa(v))
+	}
+	return values
+}
+
+func (p IntRange) Sample(rnd *rand.Rand) string {
+	n := (p.Max-p.Min)/p.step() + 1
+	return strconv.Itoa(p.Min + rnd.Intn(n)*p.step())
+}
+
+// FloatRange is a real parameter in [Min, Max]. Grid search tries Steps evenly spaced
+// values; with Log they are spaced (and sampled) on a logarithmic scale.
+type FloatRange struct {
+	Name     string
+	Min, Max float64
+	Steps    int
+	Log      bool
+}
+
+func (p FloatRange) Key() string { return p.Name }
+
+func (p FloatRange) Grid() []string {
+	if p.Steps <= 1 {
+		return []string{formatFloat(p.Min)}
+	}
+	values := make([]string, p.Steps)
+	for i := range values {
+		values[i] = formatFloat(p.at(float64(i) / float64(p.Steps-1)))
+	}
+	return values
+}
+
+func (p FloatRange) Sample(rnd *rand.Rand) string {
+	return formatFloat(p.at(rnd.Float64()))
+}
+
+// at map t in [0, 1] onto the range
+func (p FloatRange) at(t float64) float64 {
+	if p.Log {
+		lo, hi := math.Log(p.Min), math.Log(p.Max)
+		return math.Exp(lo + t*(hi-lo))
+	}
+	return p.Min + t*(p.Max-p.Min)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
+
+// Space is the set of parameters searched over
+type Space []Param
+
+// grid get the cartesian product of the grids of every parameter
+func (s Space) grid() []xgboost.Params {
+	combos := []xgboost.Params{{}}
+	for _, p := range s {
+		var next []xgboost.Params
+		for _, combo := range combos {
+			for _, v := range p.Grid() {
+				params := xgboost.Params{}
+				for k, cv := range combo {
+					params[k] = cv
+				}
+				params[p.Key()] = v
+				next = append(next, params)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// sample draw n random parameter combinations
+func (s Space) sample(rnd *rand.Rand, n int) []xgboost.Params {
+	combos := make([]xgboost.Params, n)
+	for i := range combos {
+		params := xgboost.Params{}
+		for _, p := range s {
+			params[p.Key()] = p.Sample(rnd)
+		}
+		combos[i] = params
+	}
+	return combos
+}